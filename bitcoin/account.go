@@ -5,29 +5,33 @@ import (
 
 	"github.com/MixinNetwork/go-safe-sdk/common"
 	"github.com/btcsuite/btcd/txscript/v2"
-	"github.com/btcsuite/btcd/wire/v2"
 )
 
 func ValueDust(chain byte) (int64, error) {
-	switch chain {
-	case ChainBitcoin:
-		return 1000, nil
-	case ChainLitecoin:
-		return 10000, nil
-	default:
-		return 0, fmt.Errorf("invalid chain %d", chain)
+	c, err := readBitcoinChain(chain)
+	if err != nil {
+		return 0, err
 	}
+	return c.Dust, nil
 }
 
 func protocolVersion(chain byte) (uint32, error) {
-	switch chain {
-	case ChainBitcoin:
-		return wire.ProtocolVersion, nil
-	case ChainLitecoin:
-		return 70015, nil
-	default:
-		return 0, fmt.Errorf("invalid chain %d", chain)
+	c, err := readBitcoinChain(chain)
+	if err != nil {
+		return 0, err
+	}
+	return c.ProtocolVersion, nil
+}
+
+func readBitcoinChain(chain byte) (*common.ChainInfo, error) {
+	c, err := common.ReadChain(chain)
+	if err != nil {
+		return nil, err
+	}
+	if c.Family != common.FamilyBitcoin {
+		return nil, fmt.Errorf("invalid chain %d", chain)
 	}
+	return c, nil
 }
 
 func ExtractPkScriptAddr(pkScript []byte, chain byte) (string, error) {
@@ -42,5 +46,5 @@ func ExtractPkScriptAddr(pkScript []byte, chain byte) (string, error) {
 	if threshold != 1 || len(addrs) != 1 || cls == txscript.NonStandardTy {
 		return "", fmt.Errorf("unsupported pkscript %d %v %d", cls, addrs, threshold)
 	}
-	return addrs[0].EncodeAddress(), nil
+	return encodeAddress(addrs[0], chain)
 }
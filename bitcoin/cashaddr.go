@@ -0,0 +1,137 @@
+package bitcoin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/address/v2"
+	"github.com/btcsuite/btcd/address/v2/bech32"
+	"github.com/btcsuite/btcd/chaincfg/v2"
+)
+
+// CashAddr encoding of Bitcoin Cash addresses
+// https://github.com/bitcoincashorg/bitcoincash.org/blob/master/spec/cashaddr.md
+
+const (
+	cashAddrCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	cashAddrTypeP2PKH = 0
+	cashAddrTypeP2SH  = 1
+)
+
+// the cashaddr prefix is the name of Bitcoin Cash network params,
+// i.e. bitcoincash, bchtest and bchreg
+func cashAddrPrefix(cfg *chaincfg.Params) string {
+	return cfg.Name
+}
+
+func encodeCashAddress(addr address.Address, cfg *chaincfg.Params) (string, error) {
+	var typ byte
+	switch addr.(type) {
+	case *address.AddressPubKeyHash:
+		typ = cashAddrTypeP2PKH
+	case *address.AddressScriptHash:
+		typ = cashAddrTypeP2SH
+	default:
+		return "", fmt.Errorf("unsupported cashaddr type %T", addr)
+	}
+	hash := addr.ScriptAddress()
+	if len(hash) != 20 {
+		return "", fmt.Errorf("invalid cashaddr hash %x", hash)
+	}
+	payload := append([]byte{typ << 3}, hash...)
+	data, err := bech32.ConvertBits(payload, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	prefix := cashAddrPrefix(cfg)
+	checksum := cashAddrPolymod(append(cashAddrExpandPrefix(prefix), append(data, make([]byte, 8)...)...))
+	for i := 0; i < 8; i++ {
+		data = append(data, byte((checksum>>(5*(7-i)))&0x1f))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteByte(':')
+	for _, d := range data {
+		sb.WriteByte(cashAddrCharset[d])
+	}
+	return sb.String(), nil
+}
+
+func decodeCashAddress(addr string, cfg *chaincfg.Params) (address.Address, error) {
+	prefix := cashAddrPrefix(cfg)
+	if strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr {
+		return nil, fmt.Errorf("invalid cashaddr case %s", addr)
+	}
+	addr = strings.ToLower(addr)
+	if !strings.Contains(addr, ":") {
+		addr = prefix + ":" + addr
+	}
+	parts := strings.Split(addr, ":")
+	if len(parts) != 2 || parts[0] != prefix {
+		return nil, fmt.Errorf("invalid cashaddr prefix %s", addr)
+	}
+
+	data := make([]byte, len(parts[1]))
+	for i, c := range parts[1] {
+		d := strings.IndexRune(cashAddrCharset, c)
+		if d < 0 {
+			return nil, fmt.Errorf("invalid cashaddr character %s", addr)
+		}
+		data[i] = byte(d)
+	}
+	if len(data) <= 8 {
+		return nil, fmt.Errorf("invalid cashaddr length %s", addr)
+	}
+	if cashAddrPolymod(append(cashAddrExpandPrefix(prefix), data...)) != 0 {
+		return nil, fmt.Errorf("invalid cashaddr checksum %s", addr)
+	}
+	payload, err := bech32.ConvertBits(data[:len(data)-8], 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 21 || payload[0]&0x07 != 0 {
+		return nil, fmt.Errorf("invalid cashaddr payload %s", addr)
+	}
+	switch payload[0] >> 3 {
+	case cashAddrTypeP2PKH:
+		return address.NewAddressPubKeyHash(payload[1:], cfg)
+	case cashAddrTypeP2SH:
+		return address.NewAddressScriptHashFromHash(payload[1:], cfg)
+	default:
+		return nil, fmt.Errorf("invalid cashaddr type %s", addr)
+	}
+}
+
+func cashAddrExpandPrefix(prefix string) []byte {
+	data := make([]byte, len(prefix)+1)
+	for i := 0; i < len(prefix); i++ {
+		data[i] = prefix[i] & 0x1f
+	}
+	return data
+}
+
+func cashAddrPolymod(values []byte) uint64 {
+	c := uint64(1)
+	for _, d := range values {
+		c0 := byte(c >> 35)
+		c = ((c & 0x07ffffffff) << 5) ^ uint64(d)
+		if c0&0x01 != 0 {
+			c ^= 0x98f2bc8e61
+		}
+		if c0&0x02 != 0 {
+			c ^= 0x79b76d99e2
+		}
+		if c0&0x04 != 0 {
+			c ^= 0xf33e5fb3c4
+		}
+		if c0&0x08 != 0 {
+			c ^= 0xae2eabe2a8
+		}
+		if c0&0x10 != 0 {
+			c ^= 0x1e4f43e470
+		}
+	}
+	return c ^ 1
+}
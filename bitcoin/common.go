@@ -11,7 +11,6 @@ import (
 	"github.com/MixinNetwork/mixin/common"
 	"github.com/btcsuite/btcd/address/v2"
 	"github.com/btcsuite/btcd/btcutil/v2/hdkeychain"
-	"github.com/btcsuite/btcd/chaincfg/v2"
 	"github.com/btcsuite/btcd/chainhash/v2"
 	"github.com/btcsuite/btcd/txscript/v2"
 	"github.com/btcsuite/btcd/wire/v2"
@@ -19,8 +18,10 @@ import (
 )
 
 const (
-	ChainBitcoin  = 1
-	ChainLitecoin = 5
+	ChainBitcoin     = commonSafe.ChainBitcoin
+	ChainLitecoin    = commonSafe.ChainLitecoin
+	ChainDogecoin    = commonSafe.ChainDogecoin
+	ChainBitcoinCash = commonSafe.ChainBitcoinCash
 
 	ValuePrecision = 8
 	ValueSatoshi   = 100000000
@@ -30,6 +31,8 @@ const (
 
 	ScriptPubKeyTypeWitnessKeyHash    = "witness_v0_keyhash"
 	ScriptPubKeyTypeWitnessScriptHash = "witness_v0_scripthash"
+	ScriptPubKeyTypePubKeyHash        = "pubkeyhash"
+	ScriptPubKeyTypeScriptHash        = "scripthash"
 	SigHashType                       = txscript.SigHashAll | txscript.SigHashAnyOneCanPay
 
 	InputTypeP2WPKHAccoutant             = 1
@@ -62,24 +65,9 @@ func ParseSatoshi(amount string) (int64, error) {
 }
 
 func ParseAddress(addr string, chain byte) ([]byte, error) {
-	switch chain {
-	case ChainBitcoin:
-		err := VerifyAddress(addr, CoinBitcoin)
-		if err != nil {
-			return nil, fmt.Errorf("bitcoin.VerifyAddress(%s) => %v", addr, err)
-		}
-	case ChainLitecoin:
-		err := VerifyAddress(addr, CoinLitecoin)
-		if err != nil {
-			return nil, fmt.Errorf("litecoin.VerifyAddress(%s) => %v", addr, err)
-		}
-	default:
-		return nil, fmt.Errorf("ParseAddress(%s, %d)", addr, chain)
-	}
-	cfg, _ := commonSafe.NetConfig(chain)
-	bda, err := address.DecodeAddress(addr, cfg)
+	bda, err := decodeAddress(addr, chain)
 	if err != nil {
-		return nil, fmt.Errorf("btcutil.DecodeAddress(%s, %d) => %v", addr, chain, err)
+		return nil, fmt.Errorf("ParseAddress(%s, %d) => %v", addr, chain, err)
 	}
 	script, err := txscript.PayToAddrScript(bda)
 	if err != nil {
@@ -92,26 +80,27 @@ func ParseSequence(lock time.Duration, chain byte) (int64, error) {
 	if lock < TimeLockMinimum || lock > TimeLockMaximum {
 		return 0, fmt.Errorf("invalid lock %d", lock)
 	}
-	blockDuration := 10 * time.Minute
-	switch chain {
-	case ChainBitcoin:
-	case ChainLitecoin:
-		blockDuration = 150 * time.Second
-	default:
+	c, err := readBitcoinChain(chain)
+	if err != nil {
+		return 0, err
 	}
 	// FIXME check litecoin timelock consensus as this may exceed 0xffff
-	lock = lock / blockDuration
+	lock = lock / c.BlockTime
 	if lock >= 0xffff {
 		lock = 0xffff
 	}
 	return int64(lock), nil
 }
 
-func CheckFinalization(num uint64, coinbase bool) bool {
-	if num >= uint64(chaincfg.MainNetParams.CoinbaseMaturity) {
-		return true
+func CheckFinalization(num uint64, coinbase bool, chain byte) (bool, error) {
+	cfg, err := commonSafe.NetConfig(chain)
+	if err != nil {
+		return false, err
 	}
-	return !coinbase && num >= TransactionConfirmations
+	if num >= uint64(cfg.CoinbaseMaturity) {
+		return true, nil
+	}
+	return !coinbase && num >= TransactionConfirmations, nil
 }
 
 func CheckDerivation(public string, chainCode []byte, maxRange uint32) error {
@@ -166,16 +155,12 @@ func DeriveBIP32WithPath(public, chaincode string, path8 []byte) (string, error)
 }
 
 func HashMessageForSignature(msg string, chain byte) ([]byte, error) {
-	var buf bytes.Buffer
-	prefix := "Bitcoin Signed Message:\n"
-	switch chain {
-	case ChainBitcoin:
-	case ChainLitecoin:
-		prefix = "Litecoin Signed Message:\n"
-	default:
-		return nil, fmt.Errorf("invalid chain %d", chain)
+	c, err := readBitcoinChain(chain)
+	if err != nil {
+		return nil, err
 	}
-	_ = wire.WriteVarString(&buf, 0, prefix)
+	var buf bytes.Buffer
+	_ = wire.WriteVarString(&buf, 0, c.MessagePrefix)
 	_ = wire.WriteVarString(&buf, 0, msg)
 	return chainhash.DoubleHashB(buf.Bytes()), nil
 }
@@ -190,33 +175,74 @@ func WriteBytes(enc *common.Encoder, b []byte) {
 }
 
 func VerifyAddress(receiver string, coin uint32) error {
-	_, err := address.DecodeAddress(receiver, netParams(coin))
+	var chain byte
+	switch coin {
+	case CoinBitcoin:
+		chain = ChainBitcoin
+	case CoinLitecoin:
+		chain = ChainLitecoin
+	case CoinDogecoin:
+		chain = ChainDogecoin
+	case CoinBitcoinCash:
+		chain = ChainBitcoinCash
+	default:
+		panic(coin)
+	}
+	_, err := decodeAddress(receiver, chain)
 	return err
 }
 
-const (
-	CoinBitcoin  = 0
-	CoinLitecoin = 2
-)
+func decodeAddress(receiver string, chain byte) (address.Address, error) {
+	cfg, err := commonSafe.NetConfig(chain)
+	if err != nil {
+		return nil, err
+	}
+	if chain == ChainBitcoinCash {
+		addr, err := decodeCashAddress(receiver, cfg)
+		if err == nil {
+			return addr, nil
+		}
+	}
+	addr, err := address.DecodeAddress(receiver, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("btcutil.DecodeAddress(%s, %d) => %v", receiver, chain, err)
+	}
+	if !addr.IsForNet(cfg) {
+		return nil, fmt.Errorf("address %s is not for network %s", receiver, cfg.Name)
+	}
+	return addr, nil
+}
 
-func netParams(coin uint32) *chaincfg.Params {
-	switch coin {
-	case CoinBitcoin:
-		return &chaincfg.MainNetParams
-	case CoinLitecoin:
-		return &chaincfg.Params{
-			Net:             0xdbb6c0fb,
-			Bech32HRPSegwit: "ltc",
+func encodeAddress(addr address.Address, chain byte) (string, error) {
+	if chain != ChainBitcoinCash {
+		return addr.EncodeAddress(), nil
+	}
+	cfg, err := commonSafe.NetConfig(chain)
+	if err != nil {
+		return "", err
+	}
+	return encodeCashAddress(addr, cfg)
+}
 
-			PubKeyHashAddrID:        0x30,
-			ScriptHashAddrID:        0x32,
-			WitnessPubKeyHashAddrID: 0x06,
-			WitnessScriptHashAddrID: 0x0A,
+func isSegwitChain(chain byte) (bool, error) {
+	cfg, err := commonSafe.NetConfig(chain)
+	if err != nil {
+		return false, err
+	}
+	return cfg.Bech32HRPSegwit != "", nil
+}
 
-			HDPublicKeyID:  [4]byte{0x01, 0x9d, 0xa4, 0x64},
-			HDPrivateKeyID: [4]byte{0x01, 0x9d, 0x9c, 0xfe},
-		}
-	default:
-		panic(coin)
+func sigHashType(chain byte) (txscript.SigHashType, error) {
+	c, err := readBitcoinChain(chain)
+	if err != nil {
+		return 0, err
 	}
+	return c.SigHashType, nil
 }
+
+const (
+	CoinBitcoin     = 0
+	CoinLitecoin    = 2
+	CoinDogecoin    = 3
+	CoinBitcoinCash = 145
+)
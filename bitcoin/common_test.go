@@ -0,0 +1,441 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/MixinNetwork/go-safe-sdk/common"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chainhash/v2"
+	"github.com/btcsuite/btcd/txscript/v2"
+	"github.com/btcsuite/btcd/wire/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	mainnet := "bc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqf8pjcn"
+	regtest := "bcrt1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqnkamhx"
+	_, err := ParseAddress(mainnet, ChainBitcoin)
+	assert.Nil(err)
+	_, err = ParseAddress(regtest, ChainBitcoin)
+	assert.NotNil(err)
+
+	final, err := CheckFinalization(100, true, ChainLitecoin)
+	assert.Nil(err)
+	assert.True(final)
+	final, err = CheckFinalization(99, true, ChainLitecoin)
+	assert.Nil(err)
+	assert.False(final)
+
+	err = common.SetNetwork(common.NetworkSignet)
+	assert.NotNil(err)
+	err = common.SetNetwork(common.NetworkRegtest)
+	assert.Nil(err)
+	defer common.SetNetwork(common.NetworkMainnet)
+
+	_, err = ParseAddress(mainnet, ChainBitcoin)
+	assert.NotNil(err)
+	script, err := ParseAddress(regtest, ChainBitcoin)
+	assert.Nil(err)
+	addr, err := ExtractPkScriptAddr(script, ChainBitcoin)
+	assert.Nil(err)
+	assert.Equal(regtest, addr)
+	err = VerifyAddress(regtest, CoinBitcoin)
+	assert.Nil(err)
+	err = VerifyAddress(mainnet, CoinBitcoin)
+	assert.NotNil(err)
+
+	err = common.SetChainNetwork(ChainLitecoin, common.NetworkSignet)
+	assert.NotNil(err)
+	cfg, err := common.NetConfig(ChainLitecoin)
+	assert.Nil(err)
+	assert.Equal("rltc", cfg.Bech32HRPSegwit)
+	litecoin := "rltc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqm4hyct"
+	script, err = ParseAddress(litecoin, ChainLitecoin)
+	assert.Nil(err)
+	addr, err = ExtractPkScriptAddr(script, ChainLitecoin)
+	assert.Nil(err)
+	assert.Equal(litecoin, addr)
+
+	final, err = CheckFinalization(60, true, ChainDogecoin)
+	assert.Nil(err)
+	assert.True(final)
+	final, err = CheckFinalization(59, true, ChainDogecoin)
+	assert.Nil(err)
+	assert.False(final)
+	final, err = CheckFinalization(60, true, ChainBitcoin)
+	assert.Nil(err)
+	assert.False(final)
+	final, err = CheckFinalization(1, false, ChainBitcoin)
+	assert.Nil(err)
+	assert.True(final)
+	_, err = CheckFinalization(1, false, 0)
+	assert.NotNil(err)
+}
+
+func TestCashAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	legacy := "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu"
+	cash := "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"
+	script, err := ParseAddress(cash, ChainBitcoinCash)
+	assert.Nil(err)
+	legacyScript, err := ParseAddress(legacy, ChainBitcoinCash)
+	assert.Nil(err)
+	assert.Equal(script, legacyScript)
+	addr, err := ExtractPkScriptAddr(script, ChainBitcoinCash)
+	assert.Nil(err)
+	assert.Equal(cash, addr)
+	err = VerifyAddress("qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", CoinBitcoinCash)
+	assert.Nil(err)
+	err = VerifyAddress("bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6b", CoinBitcoinCash)
+	assert.NotNil(err)
+	err = VerifyAddress(legacy, CoinDogecoin)
+	assert.NotNil(err)
+}
+
+func TestLegacyTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	h := sha256.Sum256([]byte("accountant"))
+	_, pub := btcec.PrivKeyFromBytes(h[:])
+	public := pub.SerializeCompressed()
+	holder := "02339baf159c94cc116562d609097ff3c3bd340a34b9f7d50cc22b8d520301a7c9"
+	signer := "03cc4b73ab9c6ac7a1c8f0d5d6a4ba2ec3b4d3b91e4fa8fa5ed4e71b6db4f0dc44"
+	observer := "0330a1b0c4b62c8aa0fe1dc2bd8fc3b36e6a01a93e1c0c0f8bed1e9c1e1c68ecd1"
+
+	for _, chain := range []byte{ChainDogecoin, ChainBitcoinCash} {
+		wsa, err := BuildSafeWitnessScript(holder, signer, observer, 6, chain)
+		assert.Nil(err)
+		for _, script := range [][]byte{public, wsa.Script} {
+			// the input is copied because the build changes its script
+			build := func(in Input) (*PartiallySignedTransaction, error) {
+				return BuildPartiallySignedTransaction([]*Input{&in}, []*Output{{Address: wsa.Address, Satoshi: 9000000}}, nil, chain)
+			}
+			input := Input{Index: 1, Satoshi: 10000000, Script: script, Sequence: uint32(wsa.Sequence)}
+			setPreviousTransaction(t, &input, chain)
+			_, err = build(Input{TransactionHash: input.TransactionHash, Index: 1, Satoshi: 10000000, Script: script, Sequence: uint32(wsa.Sequence)})
+			assert.NotNil(err)
+			mismatch := input
+			mismatch.Satoshi = 20000000
+			_, err = build(mismatch)
+			assert.NotNil(err)
+			pkt, err := build(input)
+			assert.Nil(err)
+
+			pin := pkt.Inputs[0]
+			assert.Nil(pin.WitnessUtxo)
+			assert.NotNil(pin.NonWitnessUtxo)
+			if len(script) == len(public) {
+				assert.Nil(pin.RedeemScript)
+			} else {
+				assert.Equal(wsa.Script, pin.RedeemScript)
+			}
+			b, err := pkt.Marshal()
+			assert.Nil(err)
+			parsed, err := UnmarshalPartiallySignedTransaction(b)
+			assert.Nil(err)
+			hash, err := pkt.SigHash(0)
+			assert.Nil(err)
+			parsedHash, err := parsed.SigHash(0)
+			assert.Nil(err)
+			assert.Equal(hash, parsedHash)
+		}
+	}
+}
+
+// setPreviousTransaction sets a previous transaction with the output spent by
+// the input at its index
+func setPreviousTransaction(t *testing.T, in *Input, chain byte) {
+	c := *in
+	c.TransactionHash = chainhash.Hash{}.String()
+	addr, err := addInput(wire.NewMsgTx(2), &c, chain)
+	assert.Nil(t, err)
+	script, err := ParseAddress(addr, chain)
+	assert.Nil(t, err)
+
+	prev := wire.NewMsgTx(1)
+	prev.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, []byte{txscript.OP_TRUE}, nil))
+	for range in.Index {
+		prev.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_TRUE}))
+	}
+	prev.AddTxOut(wire.NewTxOut(in.Satoshi, script))
+	var buf bytes.Buffer
+	err = prev.SerializeNoWitness(&buf)
+	assert.Nil(t, err)
+	in.TransactionHash = prev.TxHash().String()
+	in.RawTransaction = hex.EncodeToString(buf.Bytes())
+}
+
+func TestSafeWitnessScript(t *testing.T) {
+	assert := assert.New(t)
+
+	holder := "02339baf159c94cc116562d609097ff3c3bd340a34b9f7d50cc22b8d520301a7c9"
+	signer := "03cc4b73ab9c6ac7a1c8f0d5d6a4ba2ec3b4d3b91e4fa8fa5ed4e71b6db4f0dc44"
+	observer := "0330a1b0c4b62c8aa0fe1dc2bd8fc3b36e6a01a93e1c0c0f8bed1e9c1e1c68ecd1"
+	for _, public := range []string{holder, signer, observer} {
+		_, err := parseBitcoinCompressedPublicKey(public)
+		assert.Nil(err)
+	}
+	sequence, err := ParseSequence(TimeLockMinimum*24*90, ChainBitcoin)
+	assert.Nil(err)
+	assert.Equal(int64(12960), sequence)
+
+	wsa, err := BuildSafeWitnessScript(holder, signer, observer, sequence, ChainBitcoin)
+	assert.Nil(err)
+	assert.Len(wsa.Address, 62)
+	assert.Equal("bc1q", wsa.Address[:4])
+	typ, err := checkScriptType(wsa.Script)
+	assert.Nil(err)
+	assert.Equal(InputTypeP2WSHMultisigHolderSigner, typ)
+	parsed, err := ParseSafeWitnessScript(wsa.Script, ChainBitcoin)
+	assert.Nil(err)
+	assert.Equal(wsa, parsed)
+	parsed, err = VerifySafeWitnessScript(wsa.Address, hex.EncodeToString(wsa.Script), ChainBitcoin)
+	assert.Nil(err)
+	assert.Equal(observer, parsed.Observer)
+	_, err = VerifySafeWitnessScript(wsa.Address, hex.EncodeToString(wsa.Script), ChainLitecoin)
+	assert.NotNil(err)
+
+	short, err := BuildSafeWitnessScript(holder, signer, observer, 6, ChainDogecoin)
+	assert.Nil(err)
+	parsed, err = ParseSafeWitnessScript(short.Script, ChainDogecoin)
+	assert.Nil(err)
+	assert.Equal(int64(6), parsed.Sequence)
+	assert.Nil(VerifyAddress(short.Address, CoinDogecoin))
+
+	_, err = BuildSafeWitnessScript(holder, signer, observer, 0, ChainBitcoin)
+	assert.NotNil(err)
+	_, err = BuildSafeWitnessScript(holder, signer, holder[:64], sequence, ChainBitcoin)
+	assert.NotNil(err)
+	tampered := bytes.Clone(wsa.Script)
+	tampered[len(tampered)-1] = txscript.OP_EQUALVERIFY
+	_, err = ParseSafeWitnessScript(tampered, ChainBitcoin)
+	assert.NotNil(err)
+}
+
+func TestFinalizeTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	var privs, publics []string
+	for _, seed := range []string{"holder", "signer", "observer"} {
+		h := sha256.Sum256([]byte(seed))
+		priv, pub := btcec.PrivKeyFromBytes(h[:])
+		privs = append(privs, hex.EncodeToString(priv.Serialize()))
+		publics = append(publics, hex.EncodeToString(pub.SerializeCompressed()))
+	}
+
+	for _, c := range []struct {
+		chain      byte
+		receiver   string
+		backup     bool
+		accountant bool
+		keeper     bool
+		signers    []int
+	}{
+		{ChainBitcoin, "bc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqf8pjcn", false, false, false, []int{0, 1}},
+		{ChainBitcoin, "bc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqf8pjcn", true, false, false, []int{2, 1}},
+		{ChainBitcoin, "bc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqf8pjcn", false, false, true, []int{0, 1}},
+		{ChainBitcoin, "bc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqf8pjcn", false, true, true, []int{0}},
+		{ChainDogecoin, "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", false, false, false, []int{1, 0}},
+		{ChainDogecoin, "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", true, false, true, []int{0, 2}},
+		{ChainDogecoin, "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", false, true, false, []int{0}},
+	} {
+		wsa, err := BuildSafeWitnessScript(publics[0], publics[1], publics[2], 144, c.chain)
+		assert.Nil(err)
+		input := &Input{
+			TransactionHash: "a41ab4ee6e0bb9e78b7fe04c9d9b2b39ddf07ba0f0b7f1b4c0d7b2e5b4e1a6f1",
+			Index:           1,
+			Satoshi:         10000000,
+			Script:          wsa.Script,
+			Sequence:        uint32(wsa.Sequence),
+			RouteBackup:     c.backup,
+		}
+		if c.accountant {
+			input.Script, _ = hex.DecodeString(publics[0])
+		}
+		if c.chain != ChainBitcoin {
+			setPreviousTransaction(t, input, c.chain)
+		}
+		pkt, err := BuildPartiallySignedTransaction([]*Input{input}, []*Output{{Address: c.receiver, Satoshi: 9000000}}, nil, c.chain)
+		assert.Nil(err)
+		b, err := pkt.Marshal()
+		assert.Nil(err)
+		raw := hex.EncodeToString(b)
+		var signed []string
+		for _, i := range c.signers {
+			s, err := SignTx(raw, privs[i], c.chain)
+			assert.Nil(err)
+			if c.keeper {
+				s = appendSigHashType(t, s)
+			}
+			signed = append(signed, s)
+		}
+
+		if len(signed) > 1 {
+			_, err = FinalizeTransaction(c.chain, signed[0])
+			assert.NotNil(err)
+		}
+		final, err := FinalizeTransaction(c.chain, signed...)
+		assert.Nil(err)
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawURLEncoding} {
+			var encoded []string
+			for _, s := range signed {
+				sb, err := hex.DecodeString(s)
+				assert.Nil(err)
+				encoded = append(encoded, enc.EncodeToString(sb))
+			}
+			res, err := FinalizeTransaction(c.chain, encoded...)
+			assert.Nil(err)
+			assert.Equal(final, res)
+		}
+		fb, err := hex.DecodeString(final)
+		assert.Nil(err)
+		var msgTx wire.MsgTx
+		err = msgTx.Deserialize(bytes.NewReader(fb))
+		assert.Nil(err)
+		if c.chain == ChainBitcoin {
+			assert.Equal(pkt.Hash(), msgTx.TxHash().String())
+		}
+
+		prev, err := pkt.inputUtxo(0)
+		assert.Nil(err)
+		fetcher := txscript.NewCannedPrevOutputFetcher(prev.PkScript, prev.Value)
+		vm, err := txscript.NewEngine(prev.PkScript, &msgTx, 0, txscript.StandardVerifyFlags, nil, txscript.NewTxSigHashes(&msgTx, fetcher), prev.Value, fetcher)
+		assert.Nil(err)
+		assert.Nil(vm.Execute())
+	}
+}
+
+func TestCombinePartiallySignedTransactions(t *testing.T) {
+	assert := assert.New(t)
+
+	var privs, publics []string
+	for _, seed := range []string{"holder", "signer", "observer"} {
+		h := sha256.Sum256([]byte(seed))
+		priv, pub := btcec.PrivKeyFromBytes(h[:])
+		privs = append(privs, hex.EncodeToString(priv.Serialize()))
+		publics = append(publics, hex.EncodeToString(pub.SerializeCompressed()))
+	}
+	wsa, err := BuildSafeWitnessScript(publics[0], publics[1], publics[2], 144, ChainBitcoin)
+	assert.Nil(err)
+	input := &Input{
+		TransactionHash: "a41ab4ee6e0bb9e78b7fe04c9d9b2b39ddf07ba0f0b7f1b4c0d7b2e5b4e1a6f1",
+		Index:           1,
+		Satoshi:         10000000,
+		Script:          wsa.Script,
+	}
+	receiver := "bc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqf8pjcn"
+	pkt, err := BuildPartiallySignedTransaction([]*Input{input}, []*Output{{Address: receiver, Satoshi: 9000000}}, nil, ChainBitcoin)
+	assert.Nil(err)
+	b, err := pkt.Marshal()
+	assert.Nil(err)
+	raw := hex.EncodeToString(b)
+
+	decode := func(s string) *PartiallySignedTransaction {
+		b, err := hex.DecodeString(s)
+		assert.Nil(err)
+		p, err := UnmarshalPartiallySignedTransaction(b)
+		assert.Nil(err)
+		return p
+	}
+	holder, err := SignTx(raw, privs[0], ChainBitcoin)
+	assert.Nil(err)
+	signer, err := SignTx(raw, privs[1], ChainBitcoin)
+	assert.Nil(err)
+	combined, err := CombinePartiallySignedTransactions(decode(holder), decode(signer), decode(holder))
+	assert.Nil(err)
+	assert.Len(combined.Inputs[0].PartialSigs, 2)
+	cb, err := combined.Marshal()
+	assert.Nil(err)
+	assert.True(CheckTransactionPartiallySignedBy(hex.EncodeToString(cb), publics[0]))
+	assert.True(CheckTransactionPartiallySignedBy(hex.EncodeToString(cb), publics[1]))
+
+	both, err := SignTx(holder, privs[1], ChainBitcoin)
+	assert.Nil(err)
+	assert.Len(decode(both).Inputs[0].PartialSigs, 2)
+	_, err = FinalizeTransaction(ChainBitcoin, both)
+	assert.Nil(err)
+
+	conflict := decode(holder)
+	der, err := ecdsa.ParseDERSignature(conflict.Inputs[0].PartialSigs[0].Signature)
+	assert.Nil(err)
+	r, s := der.R(), der.S()
+	rb, sb := r.Bytes(), s.Negate().Bytes()
+	var highS []byte
+	for _, n := range [][]byte{rb[:], sb[:]} {
+		n = bytes.TrimLeft(n, "\x00")
+		if n[0]&0x80 != 0 {
+			n = append([]byte{0}, n...)
+		}
+		highS = append(highS, 0x02, byte(len(n)))
+		highS = append(highS, n...)
+	}
+	conflict.Inputs[0].PartialSigs[0].Signature = append([]byte{0x30, byte(len(highS))}, highS...)
+	_, err = CombinePartiallySignedTransactions(decode(holder), conflict)
+	assert.NotNil(err)
+
+	invalid := decode(signer)
+	invalid.Inputs[0].PartialSigs[0].PubKey = conflict.Inputs[0].PartialSigs[0].PubKey
+	_, err = CombinePartiallySignedTransactions(decode(holder), invalid)
+	assert.NotNil(err)
+
+	other, err := BuildPartiallySignedTransaction([]*Input{input}, []*Output{{Address: receiver, Satoshi: 8000000}}, nil, ChainBitcoin)
+	assert.Nil(err)
+	_, err = CombinePartiallySignedTransactions(decode(holder), other)
+	assert.NotNil(err)
+	_, err = CombinePartiallySignedTransactions()
+	assert.NotNil(err)
+}
+
+func TestFinalizeKeeperTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	// signed by the keeper holder, whose partial signature ends with 0x81
+	raw := "70736274ff0100a402000000016daf0a2ca612879093698c5ab6dbcff372e893137d5dfda23615e1489f5e07210000000000ffffffff0310270000000000002200204a8f0888cc30695a20c71ae0d119f4c09743c0d03a7db52774d06c49a52d081a905f0100000000002200204a8f0888cc30695a20c71ae0d119f4c09743c0d03a7db52774d06c49a52d081a0000000000000000126a104525b641cc6e4ed1b2bb0713b786da6b000000000001007d0100000001a93da9d71875dac610ece59c07a6574211bba14adf01ecf6b601de93538b5f5f0000000000ffffffff02a0860100000000002200204a8f0888cc30695a20c71ae0d119f4c09743c0d03a7db52774d06c49a52d081a1dbd00000000000016001409eb71fab8358daa65e08d2e148568b83fe075880000000001012ba0860100000000002200204a8f0888cc30695a20c71ae0d119f4c09743c0d03a7db52774d06c49a52d081a22020208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fad47304402206c9adbfea684f9dca42700db018a6aaebbee1f679f553e871351031ccdbff3510220064eeed0c51e0a018b4c275e6585fd81d686c106be1708507a1bd4813affb7a88101030481000000010578210208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fadac7c2103e17978200e8961fc87358898db7b0d5686aa4f14935d418de9b533d14922a4b3ac937c8292632103c8f64e27a2f3ae961a57184841df19e7d8708ddbc998f0c5abc7197ead70931fad02b001b2926893528722060208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fad04f5c895ac220603c8f64e27a2f3ae961a57184841df19e7d8708ddbc998f0c5abc7197ead70931f04d3886f84220603e17978200e8961fc87358898db7b0d5686aa4f14935d418de9b533d14922a4b30483f05fb600010178210208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fadac7c2103e17978200e8961fc87358898db7b0d5686aa4f14935d418de9b533d14922a4b3ac937c8292632103c8f64e27a2f3ae961a57184841df19e7d8708ddbc998f0c5abc7197ead70931fad02b001b2926893528722020208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fad04f5c895ac220203c8f64e27a2f3ae961a57184841df19e7d8708ddbc998f0c5abc7197ead70931f04d3886f84220203e17978200e8961fc87358898db7b0d5686aa4f14935d418de9b533d14922a4b30483f05fb600010178210208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fadac7c2103e17978200e8961fc87358898db7b0d5686aa4f14935d418de9b533d14922a4b3ac937c8292632103c8f64e27a2f3ae961a57184841df19e7d8708ddbc998f0c5abc7197ead70931fad02b001b2926893528722020208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fad04f5c895ac220203c8f64e27a2f3ae961a57184841df19e7d8708ddbc998f0c5abc7197ead70931f04d3886f84220203e17978200e8961fc87358898db7b0d5686aa4f14935d418de9b533d14922a4b30483f05fb60000"
+	holder := "0208134c3bb3263598db7f28cb631b34f81d34bfdf3cee163da7c41b6434e92fad"
+	b, err := hex.DecodeString(raw)
+	assert.Nil(err)
+	pkt, err := UnmarshalPartiallySignedTransaction(b)
+	assert.Nil(err)
+	sig := pkt.Inputs[0].PartialSigs[0].Signature
+	assert.Equal(byte(SigHashType), sig[len(sig)-1])
+
+	// the holder signature is verified without the sighash type, so the
+	// missing signer signature is the only error
+	_, err = CombinePartiallySignedTransactions(pkt)
+	assert.Nil(err)
+	err = pkt.Finalize(ChainBitcoin)
+	assert.ErrorContains(err, "input 0 not signed by holder and signer")
+
+	hash, err := pkt.SigHash(0)
+	assert.Nil(err)
+	assert.Nil(VerifySignatureDER(holder, hash, sig[:len(sig)-1]))
+	wsa, err := ParseSafeWitnessScript(pkt.Inputs[0].WitnessScript, ChainBitcoin)
+	assert.Nil(err)
+	assert.Equal(holder, wsa.Holder)
+}
+
+// appendSigHashType appends the sighash type to the partial signatures, the
+// same as the keeper does
+func appendSigHashType(t *testing.T, raw string) string {
+	b, err := hex.DecodeString(raw)
+	assert.Nil(t, err)
+	pkt, err := UnmarshalPartiallySignedTransaction(b)
+	assert.Nil(t, err)
+	for i := range pkt.Inputs {
+		pin := &pkt.Inputs[i]
+		for _, ps := range pin.PartialSigs {
+			ps.Signature = append(ps.Signature, byte(pin.SighashType))
+		}
+	}
+	b, err = pkt.Marshal()
+	assert.Nil(t, err)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,108 @@
+package bitcoin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MixinNetwork/go-safe-sdk/common"
+)
+
+var _ ScannerRPC = (*MultiRPCClient)(nil)
+
+// MultiRPCClient fails over among several endpoints of the same chain, and
+// with Quorum the deposit outputs must be agreed by a majority
+type MultiRPCClient struct {
+	*common.RPCPool[*RPCClient]
+
+	Quorum bool
+}
+
+func NewMultiRPCClient(chain byte, configs ...RPCConfig) (*MultiRPCClient, error) {
+	var clients []*RPCClient
+	for _, config := range configs {
+		clients = append(clients, NewRPCClient(chain, config))
+	}
+	pool, err := common.NewRPCPool(clients, func(ctx context.Context, c *RPCClient) (int64, error) {
+		return c.GetBlockHeight(ctx)
+	}, isEndpointError)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiRPCClient{RPCPool: pool}, nil
+}
+
+// GetBlockHeight checks the health of all endpoints, and returns the highest
+// height of those not lagging
+func (m *MultiRPCClient) GetBlockHeight(ctx context.Context) (int64, error) {
+	return m.CheckHealth(ctx)
+}
+
+func (m *MultiRPCClient) GetBlockHash(ctx context.Context, num int64) (string, error) {
+	return quorumRead(ctx, m, func(ctx context.Context, c *RPCClient) (string, error) {
+		return c.GetBlockHash(ctx, num)
+	})
+}
+
+func (m *MultiRPCClient) GetBlock(ctx context.Context, hash string) (*RPCBlock, error) {
+	return common.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (*RPCBlock, error) {
+		return c.GetBlock(ctx, hash)
+	})
+}
+
+func (m *MultiRPCClient) GetBlockWithTransactions(ctx context.Context, hash string) (*RPCBlockWithTransactions, error) {
+	return common.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (*RPCBlockWithTransactions, error) {
+		return c.GetBlockWithTransactions(ctx, hash)
+	})
+}
+
+func (m *MultiRPCClient) GetTransaction(ctx context.Context, hash string) (*RPCTransaction, error) {
+	return common.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (*RPCTransaction, error) {
+		return c.GetTransaction(ctx, hash)
+	})
+}
+
+// GetTransactionOutput compares the output with its height, so the quorum
+// fails when the transaction is not yet in the blocks of all endpoints
+func (m *MultiRPCClient) GetTransactionOutput(ctx context.Context, hash string, index int64) (*RPCTransaction, *Output, error) {
+	type result struct {
+		Transaction *RPCTransaction `json:"-"`
+		Output      *Output
+	}
+	res, err := quorumRead(ctx, m, func(ctx context.Context, c *RPCClient) (*result, error) {
+		tx, output, err := c.GetTransactionOutput(ctx, hash, index)
+		return &result{tx, output}, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Transaction, res.Output, nil
+}
+
+func (m *MultiRPCClient) EstimateSmartFee(ctx context.Context) (int64, error) {
+	return common.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (int64, error) {
+		return c.EstimateSmartFee(ctx)
+	})
+}
+
+func (m *MultiRPCClient) SendRawTransaction(ctx context.Context, raw string) (string, error) {
+	return common.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (string, error) {
+		return c.SendRawTransaction(ctx, raw)
+	})
+}
+
+func quorumRead[T any](ctx context.Context, m *MultiRPCClient, fn func(context.Context, *RPCClient) (T, error)) (T, error) {
+	if m.Quorum {
+		return common.RPCQuorum(ctx, m.RPCPool, fn)
+	}
+	return common.RPCFailover(ctx, m.RPCPool, fn)
+}
+
+// isEndpointError is false for the JSON-RPC errors of the call, e.g. unknown
+// transaction, except when the node is still loading the blocks
+func isEndpointError(err error) bool {
+	var re *RPCError
+	if errors.As(err, &re) {
+		return re.Code == -28 // RPC_IN_WARMUP
+	}
+	return true
+}
@@ -0,0 +1,318 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/address/v2"
+	"github.com/btcsuite/btcd/psbt/v2"
+	"github.com/btcsuite/btcd/txscript/v2"
+	"github.com/btcsuite/btcd/wire/v2"
+)
+
+// CombinePartiallySignedTransactions merges the partial signatures collected
+// out of band, all psbts must share the same unsigned transaction, and each
+// signature is verified against the input SigHash
+func CombinePartiallySignedTransactions(psbts ...*PartiallySignedTransaction) (*PartiallySignedTransaction, error) {
+	if len(psbts) == 0 {
+		return nil, fmt.Errorf("no psbt to combine")
+	}
+	b, err := psbts[0].Marshal()
+	if err != nil {
+		return nil, err
+	}
+	combined, err := UnmarshalPartiallySignedTransaction(b)
+	if err != nil {
+		return nil, err
+	}
+	for i := range combined.Inputs {
+		combined.Inputs[i].PartialSigs = nil
+	}
+	for _, other := range psbts {
+		err := combined.Combine(other)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return combined, nil
+}
+
+// Combine merges the verified partial signatures of other into the transaction,
+// and rejects a different signature from the same public key
+func (raw *PartiallySignedTransaction) Combine(other *PartiallySignedTransaction) error {
+	err := raw.checkSameUnsignedTransaction(other)
+	if err != nil {
+		return err
+	}
+	for i := range raw.Inputs {
+		pin := &raw.Inputs[i]
+		hash, err := other.SigHash(i)
+		if err != nil {
+			return err
+		}
+		for _, ps := range other.Inputs[i].PartialSigs {
+			public := hex.EncodeToString(ps.PubKey)
+			err := VerifySignatureDER(public, hash, partialSignatureDER(&other.Inputs[i], ps.Signature))
+			if err != nil {
+				return fmt.Errorf("input %d: %v", i, err)
+			}
+			sig := partialSignatureOf(pin, ps.PubKey)
+			switch {
+			case sig == nil:
+				pin.PartialSigs = append(pin.PartialSigs, ps)
+			case !bytes.Equal(sig, ps.Signature):
+				return fmt.Errorf("input %d conflicting signatures from %s", i, public)
+			}
+		}
+	}
+	return nil
+}
+
+func (raw *PartiallySignedTransaction) checkSameUnsignedTransaction(other *PartiallySignedTransaction) error {
+	var rb, ob bytes.Buffer
+	err := raw.UnsignedTx.Serialize(&rb)
+	if err != nil {
+		return err
+	}
+	err = other.UnsignedTx.Serialize(&ob)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(rb.Bytes(), ob.Bytes()) || len(raw.Inputs) != len(other.Inputs) {
+		return fmt.Errorf("psbt mismatch %s %s", raw.Hash(), other.Hash())
+	}
+	for i := range raw.Inputs {
+		rin, oin := raw.Inputs[i], other.Inputs[i]
+		if !bytes.Equal(rin.WitnessScript, oin.WitnessScript) || !bytes.Equal(rin.RedeemScript, oin.RedeemScript) {
+			return fmt.Errorf("psbt input %d script mismatch", i)
+		}
+		if rin.SighashType != oin.SighashType {
+			return fmt.Errorf("psbt input %d mismatch", i)
+		}
+		rutxo, err := raw.inputUtxo(i)
+		if err != nil {
+			return err
+		}
+		outxo, err := other.inputUtxo(i)
+		if err != nil {
+			return err
+		}
+		if !psbt.TxOutsEqual(rutxo, outxo) {
+			return fmt.Errorf("psbt input %d utxo mismatch", i)
+		}
+	}
+	return nil
+}
+
+// Finalize builds the final witness, or script sig on chains without segwit,
+// for each input from the partial signatures. The input with maximum
+// sequence is spent by holder and signer, otherwise it is the RouteBackup
+// path spent by observer with either holder or signer. An accountant input
+// is spent by the signature of its public key.
+func (raw *PartiallySignedTransaction) Finalize(chain byte) error {
+	segwit, err := isSegwitChain(chain)
+	if err != nil {
+		return err
+	}
+	for i := range raw.UnsignedTx.TxIn {
+		pin := &raw.Inputs[i]
+		utxo, err := raw.inputUtxo(i)
+		if err != nil {
+			return err
+		}
+		script := raw.inputScript(i, utxo)
+		var stack [][]byte
+		if txscript.IsPayToWitnessPubKeyHash(script) || txscript.IsPayToPubKeyHash(script) {
+			stack, err = raw.accountantInputStack(i, script)
+		} else {
+			stack, err = raw.safeInputStack(i, script, chain)
+		}
+		if err != nil {
+			return err
+		}
+
+		if segwit {
+			var buf bytes.Buffer
+			err = psbt.WriteTxWitness(&buf, stack)
+			if err != nil {
+				return err
+			}
+			pin.FinalScriptWitness = buf.Bytes()
+		} else {
+			builder := txscript.NewScriptBuilder()
+			for _, item := range stack {
+				builder.AddData(item)
+			}
+			sigScript, err := builder.Script()
+			if err != nil {
+				return err
+			}
+			pin.FinalScriptSig = sigScript
+		}
+		pin.PartialSigs = nil
+		pin.SighashType = 0
+		pin.WitnessScript = nil
+		pin.RedeemScript = nil
+	}
+	return nil
+}
+
+func (raw *PartiallySignedTransaction) safeInputStack(idx int, script []byte, chain byte) ([][]byte, error) {
+	wsa, err := ParseSafeWitnessScript(script, chain)
+	if err != nil {
+		return nil, fmt.Errorf("input %d: %v", idx, err)
+	}
+	holder, signer, observer, err := raw.safeInputSignatures(idx, wsa)
+	if err != nil {
+		return nil, err
+	}
+	if raw.UnsignedTx.TxIn[idx].Sequence == MaxTransactionSequence {
+		if holder == nil || signer == nil {
+			return nil, fmt.Errorf("input %d not signed by holder and signer", idx)
+		}
+		observer = nil
+	} else {
+		if observer == nil || (holder == nil && signer == nil) {
+			return nil, fmt.Errorf("input %d not signed by observer", idx)
+		}
+		if holder != nil {
+			signer = nil
+		}
+	}
+
+	// the script consumes the holder signature first, then signer and observer
+	return [][]byte{observer, signer, holder, script}, nil
+}
+
+// The accountant input is P2WPKH, or P2PKH on chains without segwit, and the
+// public key is from the partial signature matching the key hash
+func (raw *PartiallySignedTransaction) accountantInputStack(idx int, script []byte) ([][]byte, error) {
+	pin := &raw.Inputs[idx]
+	hash, err := raw.SigHash(idx)
+	if err != nil {
+		return nil, err
+	}
+	pkh := script[2:22]
+	if txscript.IsPayToPubKeyHash(script) {
+		pkh = script[3:23]
+	}
+	for _, ps := range pin.PartialSigs {
+		if !bytes.Equal(address.Hash160(ps.PubKey), pkh) {
+			continue
+		}
+		sig := partialSignatureDER(pin, ps.Signature)
+		err := VerifySignatureDER(hex.EncodeToString(ps.PubKey), hash, sig)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %v", idx, err)
+		}
+		return [][]byte{append(bytes.Clone(sig), byte(inputSigHashType(pin))), ps.PubKey}, nil
+	}
+	return nil, fmt.Errorf("input %d not signed by accountant", idx)
+}
+
+// Extract returns the fully signed transaction of a finalized psbt
+func (raw *PartiallySignedTransaction) Extract() (*wire.MsgTx, error) {
+	return psbt.Extract(raw.Packet)
+}
+
+// FinalizeTransaction combines the signed psbts in hex, standard base64 or
+// raw url base64, then returns the raw transaction hex for
+// RPCClient.SendRawTransaction
+func FinalizeTransaction(chain byte, raws ...string) (string, error) {
+	var psbts []*PartiallySignedTransaction
+	for _, r := range raws {
+		b, err := hex.DecodeString(r)
+		if err != nil {
+			b, err = base64.StdEncoding.DecodeString(r)
+		}
+		if err != nil {
+			b, err = base64.RawURLEncoding.DecodeString(r)
+		}
+		if err != nil {
+			return "", err
+		}
+		pkt, err := UnmarshalPartiallySignedTransaction(b)
+		if err != nil {
+			return "", err
+		}
+		psbts = append(psbts, pkt)
+	}
+	pkt, err := CombinePartiallySignedTransactions(psbts...)
+	if err != nil {
+		return "", err
+	}
+	err = pkt.Finalize(chain)
+	if err != nil {
+		return "", err
+	}
+	msgTx, err := pkt.Extract()
+	if err != nil {
+		return "", err
+	}
+	encoding := wire.BaseEncoding
+	if msgTx.HasWitness() {
+		encoding = wire.WitnessEncoding
+	}
+	b, err := MarshalWiredTransaction(msgTx, encoding, chain)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// The partial signatures get the sighash type appended unless they have it
+func (raw *PartiallySignedTransaction) safeInputSignatures(idx int, wsa *WitnessScriptAccount) ([]byte, []byte, []byte, error) {
+	pin := &raw.Inputs[idx]
+	hash, err := raw.SigHash(idx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var sigs [][]byte
+	for _, public := range []string{wsa.Holder, wsa.Signer, wsa.Observer} {
+		pub, _ := hex.DecodeString(public)
+		sig := partialSignatureOf(pin, pub)
+		if sig == nil {
+			sigs = append(sigs, nil)
+			continue
+		}
+		sig = partialSignatureDER(pin, sig)
+		err := VerifySignatureDER(public, hash, sig)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("input %d: %v", idx, err)
+		}
+		sigs = append(sigs, append(bytes.Clone(sig), byte(inputSigHashType(pin))))
+	}
+	return sigs[0], sigs[1], sigs[2], nil
+}
+
+func partialSignatureOf(pin *psbt.PInput, pub []byte) []byte {
+	for _, ps := range pin.PartialSigs {
+		if bytes.Equal(ps.PubKey, pub) {
+			return ps.Signature
+		}
+	}
+	return nil
+}
+
+// partialSignatureDER returns the DER signature without the sighash type,
+// which is appended to the partial signatures by BIP 174 signers, e.g. the
+// keeper, but not by SignTx. The DER length is checked because the parser
+// ignores the trailing bytes.
+func partialSignatureDER(pin *psbt.PInput, sig []byte) []byte {
+	if len(sig) < 2 || len(sig) != int(sig[1])+3 {
+		return sig
+	}
+	if sig[len(sig)-1] != byte(inputSigHashType(pin)) {
+		return sig
+	}
+	return sig[:len(sig)-1]
+}
+
+func inputSigHashType(pin *psbt.PInput) txscript.SigHashType {
+	if pin.SighashType == 0 {
+		return SigHashType
+	}
+	return pin.SighashType
+}
@@ -2,11 +2,14 @@ package bitcoin
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/MixinNetwork/go-safe-sdk/common"
@@ -56,12 +59,70 @@ type RPCBlockWithTransactions struct {
 	Tx     []*RPCTransaction `json:"tx"`
 }
 
-func RPCGetTransactionOutput(chain byte, rpc, hash string, index int64) (*RPCTransaction, *Output, error) {
+type RPCConfig struct {
+	Endpoint string
+	Username string
+	Password string
+	// bitcoind writes the .cookie file on each start, and it is read for
+	// every request when Username is empty
+	CookieFile string
+	Timeout    time.Duration
+	// requests in one JSON-RPC batch, and batches sent concurrently
+	BatchSize    int
+	BatchWorkers int
+}
+
+type RPCClient struct {
+	chain  byte
+	config RPCConfig
+	client *http.Client
+}
+
+type RPCRequest struct {
+	Method string
+	Params []any
+	Result any
+	Error  error
+}
+
+type RPCError struct {
+	Code    int64  `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%d %s", e.Code, e.Message)
+}
+
+// All clients share the transport to reuse connections to the same node
+var rpcTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// NewRPCClient uses the defaults for the zero values of config, and the batch
+// calls fail with invalid BatchSize or BatchWorkers
+func NewRPCClient(chain byte, config RPCConfig) *RPCClient {
+	if config.Timeout == 0 {
+		config.Timeout = 20 * time.Second
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 100
+	}
+	if config.BatchWorkers == 0 {
+		config.BatchWorkers = 4
+	}
+	return &RPCClient{
+		chain:  chain,
+		config: config,
+		client: &http.Client{Transport: rpcTransport, Timeout: config.Timeout},
+	}
+}
+
+func (c *RPCClient) GetTransactionOutput(ctx context.Context, hash string, index int64) (*RPCTransaction, *Output, error) {
+	chain := c.chain
 	cfg, err := common.NetConfig(chain)
 	if err != nil {
 		return nil, nil, err
 	}
-	tx, err := RPCGetTransaction(chain, rpc, hash)
+	tx, err := c.GetTransaction(ctx, hash)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -70,7 +131,14 @@ func RPCGetTransactionOutput(chain byte, rpc, hash string, index int64) (*RPCTra
 	}
 	out := tx.Vout[index]
 	skt := out.ScriptPubKey.Type
-	if skt != ScriptPubKeyTypeWitnessScriptHash && skt != ScriptPubKeyTypeWitnessKeyHash {
+	segwit, err := isSegwitChain(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case segwit && (skt == ScriptPubKeyTypeWitnessScriptHash || skt == ScriptPubKeyTypeWitnessKeyHash):
+	case !segwit && (skt == ScriptPubKeyTypeScriptHash || skt == ScriptPubKeyTypePubKeyHash):
+	default:
 		return nil, nil, nil
 	}
 	if out.ScriptPubKey.Address == "" {
@@ -84,13 +152,13 @@ func RPCGetTransactionOutput(chain byte, rpc, hash string, index int64) (*RPCTra
 	output := &Output{
 		Address:  out.ScriptPubKey.Address,
 		Satoshi:  satoshi.IntPart(),
-		Coinbase: len(tx.Vin) == 0 && tx.Vin[0].Coinbase != "",
+		Coinbase: len(tx.Vin) > 0 && tx.Vin[0].Coinbase != "",
 	}
 
 	if tx.BlockHash == "" { // mempool
 		output.Height = ^uint64(0)
 	} else {
-		block, err := RPCGetBlock(rpc, tx.BlockHash)
+		block, err := c.GetBlock(ctx, tx.BlockHash)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -124,54 +192,130 @@ func RPCGetTransactionOutput(chain byte, rpc, hash string, index int64) (*RPCTra
 	if err != nil {
 		return nil, nil, err
 	}
-	if addr.EncodeAddress() != output.Address {
+	encoded, err := encodeAddress(addr, chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	if encoded != output.Address {
 		return nil, nil, nil
 	}
 
 	return tx, output, nil
 }
 
-func RPCGetTransactionSender(chain byte, rpc string, tx *RPCTransaction) (string, error) {
-	if tx.Vin[0].Coinbase != "" {
-		return tx.Vin[0].Coinbase, nil
+func (c *RPCClient) GetTransactionSender(ctx context.Context, tx *RPCTransaction) (string, error) {
+	if len(tx.Vin) == 0 {
+		return "", fmt.Errorf("transaction %s without inputs", tx.TxId)
+	}
+	in := tx.Vin[0]
+	if in.Coinbase != "" {
+		return in.Coinbase, nil
 	}
-	itx, err := RPCGetTransaction(chain, rpc, tx.Vin[0].TxId)
+	itx, err := c.GetTransaction(ctx, in.TxId)
 	if err != nil {
 		return "", err
 	}
-	return itx.Vout[tx.Vin[0].VOUT].ScriptPubKey.Address, nil
+	if in.VOUT < 0 || int64(len(itx.Vout)) <= in.VOUT {
+		return "", fmt.Errorf("invalid input %s:%d", in.TxId, in.VOUT)
+	}
+	return itx.Vout[in.VOUT].ScriptPubKey.Address, nil
 }
 
-func RPCGetTransaction(chain byte, rpc, hash string) (*RPCTransaction, error) {
-	res, err := callBitcoinRPC(rpc, "getrawtransaction", []any{hash, 1})
-	if err != nil {
-		return nil, err
+// GetTransactionSenders fetches the first inputs of all transactions in
+// batches, and reports the error of each transaction at the same index
+func (c *RPCClient) GetTransactionSenders(ctx context.Context, txs []*RPCTransaction) ([]string, []error) {
+	senders := make([]string, len(txs))
+	errs := make([]error, len(txs))
+	var hashes []string
+	for _, tx := range txs {
+		if len(tx.Vin) > 0 && tx.Vin[0].Coinbase == "" {
+			hashes = append(hashes, tx.Vin[0].TxId)
+		}
 	}
+	inputs, inputErrs := c.GetTransactions(ctx, hashes)
+	for i, tx := range txs {
+		if len(tx.Vin) == 0 {
+			errs[i] = fmt.Errorf("transaction %s without inputs", tx.TxId)
+			continue
+		}
+		in := tx.Vin[0]
+		if in.Coinbase != "" {
+			senders[i] = in.Coinbase
+			continue
+		}
+		itx, err := inputs[0], inputErrs[0]
+		inputs, inputErrs = inputs[1:], inputErrs[1:]
+		switch {
+		case err != nil:
+			errs[i] = err
+		case in.VOUT < 0 || int64(len(itx.Vout)) <= in.VOUT:
+			errs[i] = fmt.Errorf("invalid input %s:%d", in.TxId, in.VOUT)
+		default:
+			senders[i] = itx.Vout[in.VOUT].ScriptPubKey.Address
+		}
+	}
+	return senders, errs
+}
+
+func (c *RPCClient) GetTransaction(ctx context.Context, hash string) (*RPCTransaction, error) {
 	var tx RPCTransaction
-	err = json.Unmarshal(res, &tx)
+	err := c.Call(ctx, "getrawtransaction", []any{hash, 1}, &tx)
 	if err != nil {
 		return nil, err
 	}
-	fixLitecoinLegacyScriptPubKeyRPC(chain, &tx)
-	return &tx, err
+	fixLegacyScriptPubKeyRPC(c.chain, &tx)
+	return &tx, nil
 }
 
-func RPCGetRawMempool(chain byte, rpc string) ([]*RPCTransaction, error) {
-	res, err := callBitcoinRPC(rpc, "getrawmempool", []any{})
+// GetTransactions sends getrawtransaction in concurrent batches, and reports
+// the error of each hash at the same index
+func (c *RPCClient) GetTransactions(ctx context.Context, hashes []string) ([]*RPCTransaction, []error) {
+	txs := make([]*RPCTransaction, len(hashes))
+	errs := make([]error, len(hashes))
+	err := common.ForEachBatch(len(hashes), c.config.BatchSize, c.config.BatchWorkers, func(start, end int) {
+		reqs := make([]*RPCRequest, end-start)
+		for i := range reqs {
+			txs[start+i] = &RPCTransaction{}
+			reqs[i] = &RPCRequest{
+				Method: "getrawtransaction",
+				Params: []any{hashes[start+i], 1},
+				Result: txs[start+i],
+			}
+		}
+		err := c.BatchCall(ctx, reqs)
+		for i, r := range reqs {
+			if err == nil {
+				errs[start+i] = r.Error
+			} else {
+				errs[start+i] = err
+			}
+			if errs[start+i] != nil {
+				txs[start+i] = nil
+				continue
+			}
+			fixLegacyScriptPubKeyRPC(c.chain, txs[start+i])
+		}
+	})
 	if err != nil {
-		return nil, err
+		for i := range errs {
+			errs[i] = err
+		}
 	}
-	var txs []string
-	err = json.Unmarshal(res, &txs)
+	return txs, errs
+}
+
+func (c *RPCClient) GetRawMempool(ctx context.Context) ([]*RPCTransaction, error) {
+	var ids []string
+	err := c.Call(ctx, "getrawmempool", []any{}, &ids)
 	if err != nil {
 		return nil, err
 	}
 
 	var transactions []*RPCTransaction
-	for _, id := range txs {
-		tx, err := RPCGetTransaction(chain, rpc, id)
-		if err != nil || tx == nil {
-			logger.Printf("bitcoin.RPCGetRawMempool(%s) => %v %v", id, tx, err)
+	txs, errs := c.GetTransactions(ctx, ids)
+	for i, tx := range txs {
+		if errs[i] != nil {
+			logger.Printf("bitcoin.GetRawMempool(%s) => %v", ids[i], errs[i])
 			continue
 		}
 		transactions = append(transactions, tx)
@@ -179,64 +323,44 @@ func RPCGetRawMempool(chain byte, rpc string) ([]*RPCTransaction, error) {
 	return transactions, nil
 }
 
-func RPCGetBlockWithTransactions(chain byte, rpc, hash string) (*RPCBlockWithTransactions, error) {
-	res, err := callBitcoinRPC(rpc, "getblock", []any{hash, 2})
-	if err != nil {
-		return nil, err
-	}
+func (c *RPCClient) GetBlockWithTransactions(ctx context.Context, hash string) (*RPCBlockWithTransactions, error) {
 	var b RPCBlockWithTransactions
-	err = json.Unmarshal(res, &b)
+	err := c.Call(ctx, "getblock", []any{hash, 2}, &b)
 	if err != nil {
 		return nil, err
 	}
 	for _, tx := range b.Tx {
-		fixLitecoinLegacyScriptPubKeyRPC(chain, tx)
+		fixLegacyScriptPubKeyRPC(c.chain, tx)
 		tx.BlockHash = hash
 	}
-	return &b, err
+	return &b, nil
 }
 
-func RPCGetBlock(rpc, hash string) (*RPCBlock, error) {
-	res, err := callBitcoinRPC(rpc, "getblock", []any{hash, 1})
-	if err != nil {
-		return nil, err
-	}
+func (c *RPCClient) GetBlock(ctx context.Context, hash string) (*RPCBlock, error) {
 	var b RPCBlock
-	err = json.Unmarshal(res, &b)
+	err := c.Call(ctx, "getblock", []any{hash, 1}, &b)
 	return &b, err
 }
 
-func RPCGetBlockHash(rpc string, num int64) (string, error) {
-	res, err := callBitcoinRPC(rpc, "getblockhash", []any{num})
-	if err != nil {
-		return "", err
-	}
+func (c *RPCClient) GetBlockHash(ctx context.Context, num int64) (string, error) {
 	var hash string
-	err = json.Unmarshal(res, &hash)
+	err := c.Call(ctx, "getblockhash", []any{num}, &hash)
 	return hash, err
 }
 
-func RPCGetBlockHeight(rpc string) (int64, error) {
-	res, err := callBitcoinRPC(rpc, "getblockchaininfo", []any{})
-	if err != nil {
-		return 0, err
-	}
+func (c *RPCClient) GetBlockHeight(ctx context.Context) (int64, error) {
 	var info struct {
 		Blocks int64 `json:"blocks"`
 	}
-	err = json.Unmarshal(res, &info)
+	err := c.Call(ctx, "getblockchaininfo", []any{}, &info)
 	return info.Blocks, err
 }
 
-func RPCEstimateSmartFee(chain byte, rpc string) (int64, error) {
-	res, err := callBitcoinRPC(rpc, "estimatesmartfee", []any{1})
-	if err != nil {
-		return 0, err
-	}
+func (c *RPCClient) EstimateSmartFee(ctx context.Context) (int64, error) {
 	var fee struct {
 		Rate float64 `json:"feerate"`
 	}
-	err = json.Unmarshal(res, &fee)
+	err := c.Call(ctx, "estimatesmartfee", []any{1}, &fee)
 	if err != nil || fee.Rate <= 0 {
 		return 0, fmt.Errorf("estimatesmartfee %f %v", fee.Rate, err)
 	}
@@ -247,32 +371,14 @@ func RPCEstimateSmartFee(chain byte, rpc string) (int64, error) {
 	return fvb, nil
 }
 
-func RPCSendRawTransaction(rpc, raw string) (string, error) {
-	res, err := callBitcoinRPC(rpc, "sendrawtransaction", []any{raw})
-	if err != nil {
-		return "", err
-	}
+func (c *RPCClient) SendRawTransaction(ctx context.Context, raw string) (string, error) {
 	var hash string
-	err = json.Unmarshal(res, &hash)
+	err := c.Call(ctx, "sendrawtransaction", []any{raw}, &hash)
 	return hash, err
 }
 
-// FIXME wait for litecoin core update to the latest rpc
-func fixLitecoinLegacyScriptPubKeyRPC(chain byte, tx *RPCTransaction) {
-	switch chain {
-	case ChainLitecoin:
-		for _, o := range tx.Vout {
-			if len(o.ScriptPubKey.LegacyAddresses) != 1 {
-				continue
-			}
-			o.ScriptPubKey.Address = o.ScriptPubKey.LegacyAddresses[0]
-		}
-	}
-}
-
-func callBitcoinRPC(rpc, method string, params []any) ([]byte, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
-
+// Call sends a single JSON-RPC request and decodes the result into result
+func (c *RPCClient) Call(ctx context.Context, method string, params []any, result any) error {
 	body, err := json.Marshal(map[string]any{
 		"method":  method,
 		"params":  params,
@@ -280,41 +386,189 @@ func callBitcoinRPC(rpc, method string, params []any) ([]byte, error) {
 		"jsonrpc": "2.0",
 	})
 	if err != nil {
-		return nil, err
+		return err
+	}
+	body, err = c.post(ctx, body)
+	if err != nil {
+		return c.buildRPCError(method, params, err)
+	}
+	var res rpcResponse
+	err = json.Unmarshal(body, &res)
+	if err != nil {
+		return fmt.Errorf("%v (%s)", c.buildRPCError(method, params, err), string(body))
+	}
+	return res.decode(c, method, params, result)
+}
+
+// BatchCall sends all requests in one JSON-RPC batch, the error of each
+// request is set to its Error field, and the returned error is only for
+// the whole batch
+func (c *RPCClient) BatchCall(ctx context.Context, reqs []*RPCRequest) error {
+	if len(reqs) == 0 {
+		return nil
+	}
+	method := fmt.Sprintf("batch(%s, %d)", reqs[0].Method, len(reqs))
+
+	msgs := make([]map[string]any, len(reqs))
+	for i, r := range reqs {
+		msgs[i] = map[string]any{
+			"method":  r.Method,
+			"params":  r.Params,
+			"id":      i,
+			"jsonrpc": "2.0",
+		}
+	}
+	body, err := json.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+	body, err = c.post(ctx, body)
+	if err != nil {
+		return c.buildRPCError(method, nil, err)
+	}
+	var results []*rpcResponse
+	err = json.Unmarshal(body, &results)
+	if err != nil {
+		return fmt.Errorf("%v (%s)", c.buildRPCError(method, nil, err), string(body))
 	}
+	for _, r := range reqs {
+		r.Error = c.buildRPCError(r.Method, r.Params, fmt.Errorf("no response"))
+	}
+	for _, res := range results {
+		if res.Id < 0 || res.Id >= int64(len(reqs)) {
+			continue
+		}
+		r := reqs[res.Id]
+		r.Error = res.decode(c, r.Method, r.Params, r.Result)
+	}
+	return nil
+}
 
-	req, err := http.NewRequest("POST", rpc, bytes.NewReader(body))
+type rpcResponse struct {
+	Id     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+func (res *rpcResponse) decode(c *RPCClient, method string, params []any, result any) error {
+	if res.Error != nil {
+		return c.buildRPCError(method, params, res.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	err := json.Unmarshal(res.Result, result)
 	if err != nil {
-		return nil, buildRPCError(rpc, method, params, err)
+		return c.buildRPCError(method, params, err)
 	}
+	return nil
+}
 
-	req.Close = true
+func (c *RPCClient) post(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
+	err = c.setAuth(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, buildRPCError(rpc, method, params, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, buildRPCError(rpc, method, params, err)
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("http status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (c *RPCClient) setAuth(req *http.Request) error {
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+		return nil
 	}
-	var result struct {
-		Data  any `json:"result"`
-		Error any `json:"error"`
+	if c.config.CookieFile == "" {
+		return nil
 	}
-	err = json.Unmarshal(body, &result)
+	cookie, err := os.ReadFile(c.config.CookieFile)
 	if err != nil {
-		return nil, fmt.Errorf("%v (%s)", buildRPCError(rpc, method, params, err), string(body))
+		return err
 	}
-	if result.Error != nil {
-		return nil, fmt.Errorf("%v (%s)", buildRPCError(rpc, method, params, err), string(body))
+	user, pass, found := strings.Cut(strings.TrimSpace(string(cookie)), ":")
+	if !found {
+		return fmt.Errorf("invalid cookie file %s", c.config.CookieFile)
 	}
+	req.SetBasicAuth(user, pass)
+	return nil
+}
+
+func (c *RPCClient) buildRPCError(method string, params []any, err error) error {
+	return fmt.Errorf("callBitcoinRPC(%s, %s, %v) => %w", c.config.Endpoint, method, params, err)
+}
+
+// Deprecated: use RPCClient.GetTransactionOutput
+func RPCGetTransactionOutput(chain byte, rpc, hash string, index int64) (*RPCTransaction, *Output, error) {
+	return NewRPCClient(chain, RPCConfig{Endpoint: rpc}).GetTransactionOutput(context.Background(), hash, index)
+}
+
+// Deprecated: use RPCClient.GetTransactionSender
+func RPCGetTransactionSender(chain byte, rpc string, tx *RPCTransaction) (string, error) {
+	return NewRPCClient(chain, RPCConfig{Endpoint: rpc}).GetTransactionSender(context.Background(), tx)
+}
+
+// Deprecated: use RPCClient.GetTransaction
+func RPCGetTransaction(chain byte, rpc, hash string) (*RPCTransaction, error) {
+	return NewRPCClient(chain, RPCConfig{Endpoint: rpc}).GetTransaction(context.Background(), hash)
+}
+
+// Deprecated: use RPCClient.GetRawMempool
+func RPCGetRawMempool(chain byte, rpc string) ([]*RPCTransaction, error) {
+	return NewRPCClient(chain, RPCConfig{Endpoint: rpc}).GetRawMempool(context.Background())
+}
+
+// Deprecated: use RPCClient.GetBlockWithTransactions
+func RPCGetBlockWithTransactions(chain byte, rpc, hash string) (*RPCBlockWithTransactions, error) {
+	return NewRPCClient(chain, RPCConfig{Endpoint: rpc}).GetBlockWithTransactions(context.Background(), hash)
+}
+
+// Deprecated: use RPCClient.GetBlock
+func RPCGetBlock(rpc, hash string) (*RPCBlock, error) {
+	return NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: rpc}).GetBlock(context.Background(), hash)
+}
+
+// Deprecated: use RPCClient.GetBlockHash
+func RPCGetBlockHash(rpc string, num int64) (string, error) {
+	return NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: rpc}).GetBlockHash(context.Background(), num)
+}
+
+// Deprecated: use RPCClient.GetBlockHeight
+func RPCGetBlockHeight(rpc string) (int64, error) {
+	return NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: rpc}).GetBlockHeight(context.Background())
+}
+
+// Deprecated: use RPCClient.EstimateSmartFee
+func RPCEstimateSmartFee(chain byte, rpc string) (int64, error) {
+	return NewRPCClient(chain, RPCConfig{Endpoint: rpc}).EstimateSmartFee(context.Background())
+}
 
-	return json.Marshal(result.Data)
+// Deprecated: use RPCClient.SendRawTransaction
+func RPCSendRawTransaction(rpc, raw string) (string, error) {
+	return NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: rpc}).SendRawTransaction(context.Background(), raw)
 }
 
-func buildRPCError(rpc, method string, params []any, err error) error {
-	return fmt.Errorf("callBitcoinRPC(%s, %s, %v) => %v", rpc, method, params, err)
+// FIXME wait for litecoin, dogecoin and bitcoin cash nodes update to the latest rpc
+func fixLegacyScriptPubKeyRPC(chain byte, tx *RPCTransaction) {
+	switch chain {
+	case ChainLitecoin, ChainDogecoin, ChainBitcoinCash:
+		for _, o := range tx.Vout {
+			if len(o.ScriptPubKey.LegacyAddresses) != 1 {
+				continue
+			}
+			o.ScriptPubKey.Address = o.ScriptPubKey.LegacyAddresses[0]
+		}
+	}
 }
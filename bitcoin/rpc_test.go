@@ -0,0 +1,418 @@
+package bitcoin
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCClient(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	handle := func(req map[string]any) map[string]any {
+		res := map[string]any{"id": req["id"], "result": nil, "error": nil}
+		switch req["method"] {
+		case "getblockchaininfo":
+			res["result"] = map[string]any{"blocks": 840000}
+		case "getblockhash":
+			res["result"] = "0000000000000000000320283a032748cef8227873ff4872689bf23f1cda83a5"
+		case "getrawtransaction":
+			res["result"] = map[string]any{
+				"txid": req["params"].([]any)[0],
+				"vout": []any{map[string]any{"value": 0.1, "n": 0, "scriptPubKey": map[string]any{"address": "bc1qsender"}}},
+			}
+		default:
+			res["error"] = map[string]any{"code": -32601, "message": "Method not found"}
+		}
+		return res
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "mixin" || pass != "safe" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var batch []map[string]any
+		if json.Unmarshal(body, &batch) == nil {
+			var results []map[string]any
+			for _, req := range batch {
+				results = append(results, handle(req))
+			}
+			json.NewEncoder(w).Encode(results)
+			return
+		}
+		var req map[string]any
+		json.Unmarshal(body, &req)
+		json.NewEncoder(w).Encode(handle(req))
+	}))
+	defer server.Close()
+
+	rpc := NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL, Username: "mixin", Password: "safe"})
+	height, err := rpc.GetBlockHeight(ctx)
+	assert.Nil(err)
+	assert.Equal(int64(840000), height)
+	err = rpc.Call(ctx, "getblockstats", []any{840000}, nil)
+	var rpcErr *RPCError
+	assert.True(errors.As(err, &rpcErr))
+	assert.Equal(int64(-32601), rpcErr.Code)
+
+	var hash string
+	reqs := []*RPCRequest{
+		{Method: "getblockhash", Params: []any{840000}, Result: &hash},
+		{Method: "getblockstats", Params: []any{840000}},
+	}
+	err = rpc.BatchCall(ctx, reqs)
+	assert.Nil(err)
+	assert.Nil(reqs[0].Error)
+	assert.Equal("0000000000000000000320283a032748cef8227873ff4872689bf23f1cda83a5", hash)
+	assert.NotNil(reqs[1].Error)
+
+	sender, err := rpc.GetTransactionSender(ctx, &RPCTransaction{Vin: []*rpcIn{{TxId: hash}}})
+	assert.Nil(err)
+	assert.Equal("bc1qsender", sender)
+	_, err = rpc.GetTransactionSender(ctx, &RPCTransaction{Vin: []*rpcIn{{TxId: hash, VOUT: 1}}})
+	assert.ErrorContains(err, "invalid input")
+	_, err = rpc.GetTransactionSender(ctx, &RPCTransaction{TxId: hash})
+	assert.ErrorContains(err, "without inputs")
+
+	cookie := filepath.Join(t.TempDir(), ".cookie")
+	err = os.WriteFile(cookie, []byte("mixin:safe\n"), 0600)
+	assert.Nil(err)
+	rpc = NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL, CookieFile: cookie})
+	hash, err = rpc.GetBlockHash(ctx, 840000)
+	assert.Nil(err)
+	assert.Len(hash, 64)
+
+	rpc = NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL, Username: "mixin"})
+	_, err = rpc.GetBlockHeight(ctx)
+	assert.NotNil(err)
+	cancel, done := context.WithCancel(ctx)
+	done()
+	_, err = NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL, CookieFile: cookie}).GetBlockHeight(cancel)
+	assert.True(errors.Is(err, context.Canceled))
+}
+
+func TestRPCClientBatch(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var batches atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var reqs []map[string]any
+		single := json.Unmarshal(body, &reqs) != nil
+		if single {
+			var req map[string]any
+			json.Unmarshal(body, &req)
+			reqs = append(reqs, req)
+		} else {
+			batches.Add(1)
+		}
+		var results []map[string]any
+		for _, req := range reqs {
+			res := map[string]any{"id": req["id"], "result": nil, "error": nil}
+			params := req["params"].([]any)
+			switch {
+			case req["method"] == "getrawmempool":
+				var ids []string
+				for i := range 250 {
+					ids = append(ids, fmt.Sprintf("%064x", i))
+				}
+				res["result"] = ids
+			case params[0] == fmt.Sprintf("%064x", 7):
+				res["error"] = map[string]any{"code": -5, "message": "No such mempool or blockchain transaction"}
+			default:
+				id := params[0].(string)
+				res["result"] = map[string]any{
+					"txid": id,
+					"vin":  []any{map[string]any{"txid": fmt.Sprintf("%064x", 7), "vout": 0}},
+					"vout": []any{map[string]any{"value": 0.1, "n": 0, "scriptPubKey": map[string]any{"address": "bc1q" + id[60:]}}},
+				}
+			}
+			results = append(results, res)
+		}
+		if single {
+			json.NewEncoder(w).Encode(results[0])
+			return
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	rpc := NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL})
+	txs, err := rpc.GetRawMempool(ctx)
+	assert.Nil(err)
+	assert.Len(txs, 249)
+	assert.Equal(int64(3), batches.Load())
+
+	hashes := []string{fmt.Sprintf("%064x", 1), fmt.Sprintf("%064x", 7), fmt.Sprintf("%064x", 2)}
+	txs, errs := rpc.GetTransactions(ctx, hashes)
+	assert.Len(txs, 3)
+	assert.Nil(errs[0])
+	assert.NotNil(errs[1])
+	assert.Nil(txs[1])
+	assert.Equal(hashes[2], txs[2].TxId)
+	invalid := NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL, BatchSize: -1})
+	_, errs = invalid.GetTransactions(ctx, hashes)
+	assert.Len(errs, 3)
+	assert.ErrorContains(errs[0], "invalid batch size -1")
+
+	txs[1] = &RPCTransaction{Vin: []*rpcIn{{Coinbase: "03a0bb0d"}}}
+	txs[2].Vin[0].TxId = hashes[0]
+	senders, errs := rpc.GetTransactionSenders(ctx, txs)
+	assert.NotNil(errs[0])
+	assert.Nil(errs[1])
+	assert.Equal("03a0bb0d", senders[1])
+	assert.Nil(errs[2])
+	assert.Equal("bc1q"+hashes[0][60:], senders[2])
+
+	txs = append(txs, &RPCTransaction{TxId: hashes[1]}, &RPCTransaction{Vin: []*rpcIn{{TxId: hashes[0], VOUT: 1}}})
+	senders, errs = rpc.GetTransactionSenders(ctx, txs)
+	assert.Len(senders, 5)
+	assert.Equal("bc1q"+hashes[0][60:], senders[2])
+	assert.ErrorContains(errs[3], "without inputs")
+	assert.ErrorContains(errs[4], "invalid input")
+}
+
+func TestScanner(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	watched := "bc1qjlvcfzvmnyttsjsnlndlp5gpuxdd552xzvxacp4lexgefgpmauuqf8pjcn"
+	script, err := ParseAddress(watched, ChainBitcoin)
+	assert.Nil(err)
+	buildTx := func(seed uint32, satoshi int64) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: seed}, nil, nil))
+		tx.AddTxOut(wire.NewTxOut(satoshi, script))
+		return tx
+	}
+	type block struct {
+		hash string
+		txs  []*wire.MsgTx
+	}
+	var mutex sync.Mutex
+	var failing string
+	chain := []*block{
+		{hash: fmt.Sprintf("%064x", 0)},
+		{hash: fmt.Sprintf("%064x", 1), txs: []*wire.MsgTx{buildTx(1, 10000)}},
+		{hash: fmt.Sprintf("%064x", 2), txs: []*wire.MsgTx{buildTx(wire.MaxPrevOutIndex, 20000)}},
+	}
+	encodeTx := func(tx *wire.MsgTx, hash string) map[string]any {
+		var buf bytes.Buffer
+		tx.Serialize(&buf)
+		vin := map[string]any{"txid": tx.TxIn[0].PreviousOutPoint.Hash.String(), "vout": 0}
+		if tx.TxIn[0].PreviousOutPoint.Index == wire.MaxPrevOutIndex {
+			vin = map[string]any{"coinbase": "03a0bb0d"}
+		}
+		return map[string]any{
+			"txid":      tx.TxHash().String(),
+			"hex":       hex.EncodeToString(buf.Bytes()),
+			"blockhash": hash,
+			"vin":       []any{vin},
+			"vout": []any{map[string]any{
+				"value":        float64(tx.TxOut[0].Value) / ValueSatoshi,
+				"n":            0,
+				"scriptPubKey": map[string]any{"address": watched, "type": ScriptPubKeyTypeWitnessScriptHash},
+			}},
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		params := req["params"].([]any)
+		if req["method"] == failing {
+			failing = ""
+			json.NewEncoder(w).Encode(map[string]any{"id": req["id"], "error": map[string]any{"code": -28, "message": "Loading block index..."}})
+			return
+		}
+		var result any
+		switch req["method"] {
+		case "getblockchaininfo":
+			result = map[string]any{"blocks": len(chain) - 1}
+		case "getblockhash":
+			result = chain[int(params[0].(float64))].hash
+		case "getblock":
+			for i, b := range chain {
+				if b.hash != params[0] {
+					continue
+				}
+				var txs []any
+				for _, tx := range b.txs {
+					if params[1].(float64) == 1 {
+						txs = append(txs, tx.TxHash().String())
+					} else {
+						txs = append(txs, encodeTx(tx, b.hash))
+					}
+				}
+				result = map[string]any{"hash": b.hash, "height": i, "tx": txs}
+			}
+		case "getrawtransaction":
+			for _, b := range chain {
+				for _, tx := range b.txs {
+					if tx.TxHash().String() == params[0] {
+						result = encodeTx(tx, b.hash)
+					}
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"id": req["id"], "result": result})
+	}))
+	defer server.Close()
+
+	scanner := NewScanner(NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL}), ChainBitcoin, 1)
+	deposits, err := scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 0)
+	assert.Equal(uint64(3), scanner.Height())
+
+	scanner = NewScanner(NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL}), ChainBitcoin, 1)
+	scanner.Watch(watched)
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 2)
+	assert.Equal(int64(10000), deposits[0].Satoshi)
+	assert.Equal(uint64(2), deposits[0].Confirmations)
+	assert.True(deposits[0].Final)
+	assert.True(deposits[1].Coinbase)
+	assert.Equal(uint64(1), deposits[1].Confirmations)
+	assert.False(deposits[1].Final)
+	rpc := NewRPCClient(ChainBitcoin, RPCConfig{Endpoint: server.URL})
+	_, output, err := rpc.GetTransactionOutput(ctx, chain[1].txs[0].TxHash().String(), 0)
+	assert.Nil(err)
+	assert.Equal(int64(10000), output.Satoshi)
+	assert.False(output.Coinbase)
+	_, output, err = rpc.GetTransactionOutput(ctx, chain[2].txs[0].TxHash().String(), 0)
+	assert.Nil(err)
+	assert.Equal(uint64(2), output.Height)
+	assert.True(output.Coinbase)
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 0)
+
+	mutex.Lock()
+	chain[2] = &block{hash: fmt.Sprintf("%064x", 22), txs: []*wire.MsgTx{buildTx(3, 30000)}}
+	chain = append(chain, &block{hash: fmt.Sprintf("%064x", 3)})
+	mutex.Unlock()
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 2)
+	assert.True(deposits[0].Removed)
+	assert.Equal(int64(20000), deposits[0].Satoshi)
+	assert.False(deposits[1].Removed)
+	assert.Equal(int64(30000), deposits[1].Satoshi)
+	assert.Equal(fmt.Sprintf("%064x", 22), deposits[1].BlockHash)
+	assert.Equal(uint64(2), deposits[1].Confirmations)
+	assert.Equal(uint64(4), scanner.Height())
+
+	mutex.Lock()
+	chain = append(chain, &block{hash: fmt.Sprintf("%064x", 4), txs: []*wire.MsgTx{buildTx(wire.MaxPrevOutIndex, 40000)}})
+	mutex.Unlock()
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 1)
+	assert.True(deposits[0].Coinbase)
+	assert.False(deposits[0].Final)
+
+	// the removed deposit is kept when the scan fails after the rollback
+	mutex.Lock()
+	chain[4] = &block{hash: fmt.Sprintf("%064x", 44)}
+	failing = "getblockchaininfo"
+	mutex.Unlock()
+	_, err = scanner.Scan(ctx)
+	assert.ErrorContains(err, "Loading block index")
+	assert.Equal(uint64(4), scanner.Height())
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 1)
+	assert.True(deposits[0].Removed)
+	assert.Equal(int64(40000), deposits[0].Satoshi)
+	assert.Equal(fmt.Sprintf("%064x", 4), deposits[0].BlockHash)
+	assert.Equal(uint64(5), scanner.Height())
+}
+
+func TestMultiRPCClient(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var configs []RPCConfig
+	hashes := []string{"00a1", "00b1", "00a1"}
+	var down, missing atomic.Bool
+	for i, height := range []int64{840000, 839999, 839990} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if i == 0 && down.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var req map[string]any
+			json.NewDecoder(r.Body).Decode(&req)
+			res := map[string]any{"id": req["id"], "result": nil, "error": nil}
+			switch req["method"] {
+			case "getblockchaininfo":
+				res["result"] = map[string]any{"blocks": height}
+			case "getblockhash":
+				res["result"] = hashes[i]
+				if i == 0 && missing.Load() {
+					res["result"], res["error"] = nil, map[string]any{"code": -8, "message": "Block height out of range"}
+				}
+			}
+			json.NewEncoder(w).Encode(res)
+		}))
+		defer server.Close()
+		configs = append(configs, RPCConfig{Endpoint: server.URL})
+	}
+	_, err := NewMultiRPCClient(ChainBitcoin)
+	assert.NotNil(err)
+	rpc, err := NewMultiRPCClient(ChainBitcoin, configs...)
+	assert.Nil(err)
+
+	height, err := rpc.GetBlockHeight(ctx)
+	assert.Nil(err)
+	assert.Equal(int64(840000), height)
+	hash, err := rpc.GetBlockHash(ctx, 839980)
+	assert.Nil(err)
+	assert.Equal("00a1", hash)
+	down.Store(true)
+	hash, err = rpc.GetBlockHash(ctx, 839980)
+	assert.Nil(err)
+	assert.Equal("00b1", hash)
+
+	rpc.Quorum = true
+	_, err = rpc.GetBlockHash(ctx, 839980)
+	assert.ErrorContains(err, "no rpc quorum")
+	down.Store(false)
+	hash, err = rpc.GetBlockHash(ctx, 839980)
+	assert.Nil(err)
+	assert.Equal("00a1", hash)
+
+	// the error of the call doesn't mark the endpoint unhealthy
+	rpc.Quorum = false
+	_, err = rpc.GetBlockHeight(ctx)
+	assert.Nil(err)
+	missing.Store(true)
+	hash, err = rpc.GetBlockHash(ctx, 839980)
+	assert.Nil(err)
+	assert.Equal("00b1", hash)
+	missing.Store(false)
+	hash, err = rpc.GetBlockHash(ctx, 839980)
+	assert.Nil(err)
+	assert.Equal("00a1", hash)
+}
@@ -0,0 +1,236 @@
+package bitcoin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const scannerReorgDepth = 144
+
+type Deposit struct {
+	TransactionHash string
+	Index           int64
+	Address         string
+	Satoshi         int64
+	Coinbase        bool
+	BlockHash       string
+	Height          uint64
+	Confirmations   uint64
+	Final           bool
+	// the deposit block is orphaned by a reorg
+	Removed bool
+}
+
+// Scanner follows the chain tip from a start height, and emits the deposits
+// to watched addresses, then emits them again when the confirmations change
+// until they are final by CheckFinalization, or removed by a reorg
+type Scanner struct {
+	rpc   ScannerRPC
+	chain byte
+
+	mutex     sync.RWMutex
+	addresses map[string]bool
+
+	// the scan state is locked during Scan, so Height never returns
+	// the height of a scan in progress
+	scanMutex sync.Mutex
+	height    uint64
+	hashes    map[uint64]string
+	deposits  []*Deposit
+	// the removed events are kept until returned by Scan without error
+	removed []*Deposit
+
+	PollInterval time.Duration
+}
+
+// ScannerRPC is the chain reader of a Scanner, implemented by both the
+// RPCClient and the MultiRPCClient
+type ScannerRPC interface {
+	GetBlockHeight(ctx context.Context) (int64, error)
+	GetBlockHash(ctx context.Context, num int64) (string, error)
+	GetBlockWithTransactions(ctx context.Context, hash string) (*RPCBlockWithTransactions, error)
+	GetTransactionOutput(ctx context.Context, hash string, index int64) (*RPCTransaction, *Output, error)
+}
+
+func NewScanner(rpc ScannerRPC, chain byte, start uint64) *Scanner {
+	return &Scanner{
+		rpc:          rpc,
+		chain:        chain,
+		addresses:    make(map[string]bool),
+		height:       start,
+		hashes:       make(map[uint64]string),
+		PollInterval: 10 * time.Second,
+	}
+}
+
+func (s *Scanner) Watch(addresses ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, a := range addresses {
+		s.addresses[a] = true
+	}
+}
+
+func (s *Scanner) Unwatch(addresses ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, a := range addresses {
+		delete(s.addresses, a)
+	}
+}
+
+func (s *Scanner) watching(address string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.addresses[address]
+}
+
+// Height returns the next block height to scan, which could be persisted
+// and used as the start of a new scanner
+func (s *Scanner) Height() uint64 {
+	s.scanMutex.Lock()
+	defer s.scanMutex.Unlock()
+	return s.height
+}
+
+// Run scans until the context is done, and stops on the first error of
+// handler, which could be called again with the same deposit after restart
+func (s *Scanner) Run(ctx context.Context, handler func(*Deposit) error) error {
+	for {
+		deposits, err := s.Scan(ctx)
+		if err != nil {
+			return err
+		}
+		for _, d := range deposits {
+			err := handler(d)
+			if err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.PollInterval):
+		}
+	}
+}
+
+// Scan rolls back the orphaned blocks, then scans all blocks to the tip and
+// returns the deposit events in order. The removed events of a rollback are
+// returned by the next successful Scan when the scan fails after it.
+func (s *Scanner) Scan(ctx context.Context) ([]*Deposit, error) {
+	s.scanMutex.Lock()
+	defer s.scanMutex.Unlock()
+
+	err := s.rollback(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := s.rpc.GetBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for ; int64(s.height) <= tip; s.height++ {
+		hash, err := s.rpc.GetBlockHash(ctx, int64(s.height))
+		if err != nil {
+			return nil, err
+		}
+		deposits, err := s.scanBlock(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		s.deposits = append(s.deposits, deposits...)
+		s.hashes[s.height] = hash
+		delete(s.hashes, s.height-scannerReorgDepth)
+	}
+
+	events := s.removed
+	var pending []*Deposit
+	for _, d := range s.deposits {
+		confirmations := uint64(tip) - d.Height + 1
+		if confirmations != d.Confirmations {
+			final, err := CheckFinalization(confirmations, d.Coinbase, s.chain)
+			if err != nil {
+				return nil, err
+			}
+			d.Confirmations, d.Final = confirmations, final
+			e := *d
+			events = append(events, &e)
+		}
+		if !d.Final {
+			pending = append(pending, d)
+		}
+	}
+	s.deposits, s.removed = pending, nil
+	return events, nil
+}
+
+// rollback walks back from the last scanned block until the hash matches
+// the chain, and keeps the removed deposits of the orphaned blocks
+func (s *Scanner) rollback(ctx context.Context) error {
+	for s.height > 0 {
+		known, found := s.hashes[s.height-1]
+		if !found {
+			break
+		}
+		hash, err := s.rpc.GetBlockHash(ctx, int64(s.height-1))
+		if err != nil {
+			return err
+		}
+		if hash == known {
+			break
+		}
+		s.height = s.height - 1
+		delete(s.hashes, s.height)
+
+		var pending []*Deposit
+		for _, d := range s.deposits {
+			if d.BlockHash != known {
+				pending = append(pending, d)
+				continue
+			}
+			e := *d
+			e.Removed = true
+			s.removed = append(s.removed, &e)
+		}
+		s.deposits = pending
+	}
+	return nil
+}
+
+func (s *Scanner) scanBlock(ctx context.Context, hash string) ([]*Deposit, error) {
+	block, err := s.rpc.GetBlockWithTransactions(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block.Height != s.height {
+		return nil, fmt.Errorf("block %s height %d %d", hash, block.Height, s.height)
+	}
+	var deposits []*Deposit
+	for _, tx := range block.Tx {
+		for _, out := range tx.Vout {
+			if out.ScriptPubKey == nil || !s.watching(out.ScriptPubKey.Address) {
+				continue
+			}
+			_, output, err := s.rpc.GetTransactionOutput(ctx, tx.TxId, out.N)
+			if err != nil {
+				return nil, err
+			}
+			if output == nil || output.Address != out.ScriptPubKey.Address {
+				continue
+			}
+			deposits = append(deposits, &Deposit{
+				TransactionHash: tx.TxId,
+				Index:           out.N,
+				Address:         output.Address,
+				Satoshi:         output.Satoshi,
+				Coinbase:        len(tx.Vin) > 0 && tx.Vin[0].Coinbase != "",
+				BlockHash:       hash,
+				Height:          block.Height,
+			})
+		}
+	}
+	return deposits, nil
+}
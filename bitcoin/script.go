@@ -0,0 +1,170 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/MixinNetwork/go-safe-sdk/common"
+	"github.com/btcsuite/btcd/address/v2"
+	"github.com/btcsuite/btcd/txscript/v2"
+)
+
+type WitnessScriptAccount struct {
+	Holder   string
+	Signer   string
+	Observer string
+	Sequence int64
+	Script   []byte
+	Address  string
+}
+
+// BuildSafeWitnessScript builds the safe account script, which is spendable
+// by holder and signer, or by observer with either of them after the sequence
+//
+// thresh(2,pk(HOLDER),s:pk(SIGNER),sj:and_v(v:pk(OBSERVER),n:older(SEQUENCE)))
+//
+// <HOLDER> OP_CHECKSIG OP_SWAP <SIGNER> OP_CHECKSIG OP_ADD OP_SWAP OP_SIZE
+// OP_0NOTEQUAL OP_IF
+// <OBSERVER> OP_CHECKSIGVERIFY <SEQUENCE> OP_CHECKSEQUENCEVERIFY OP_0NOTEQUAL
+// OP_ENDIF
+// OP_ADD 2 OP_EQUAL
+func BuildSafeWitnessScript(holder, signer, observer string, sequence int64, chain byte) (*WitnessScriptAccount, error) {
+	if sequence < 1 || sequence > 0xffff {
+		return nil, fmt.Errorf("invalid sequence %d", sequence)
+	}
+	var pubKeys [][]byte
+	for _, public := range []string{holder, signer, observer} {
+		pub, err := parseBitcoinCompressedPublicKey(public)
+		if err != nil || len(pub.ScriptAddress()) != 33 {
+			return nil, fmt.Errorf("invalid public key %s", public)
+		}
+		pubKeys = append(pubKeys, pub.ScriptAddress())
+	}
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(pubKeys[0])
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_SWAP)
+	builder.AddData(pubKeys[1])
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_ADD)
+	builder.AddOp(txscript.OP_SWAP)
+	builder.AddOp(txscript.OP_SIZE)
+	builder.AddOp(txscript.OP_0NOTEQUAL)
+	builder.AddOp(txscript.OP_IF)
+	builder.AddData(pubKeys[2])
+	builder.AddOp(txscript.OP_CHECKSIGVERIFY)
+	builder.AddInt64(sequence)
+	builder.AddOp(txscript.OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_0NOTEQUAL)
+	builder.AddOp(txscript.OP_ENDIF)
+	builder.AddOp(txscript.OP_ADD)
+	builder.AddInt64(2)
+	builder.AddOp(txscript.OP_EQUAL)
+	script, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := witnessScriptAddress(script, chain)
+	if err != nil {
+		return nil, err
+	}
+	return &WitnessScriptAccount{
+		Holder:   hex.EncodeToString(pubKeys[0]),
+		Signer:   hex.EncodeToString(pubKeys[1]),
+		Observer: hex.EncodeToString(pubKeys[2]),
+		Sequence: sequence,
+		Script:   script,
+		Address:  addr,
+	}, nil
+}
+
+// ParseSafeWitnessScript extracts the public keys and sequence from the
+// script, and rejects any script not built by BuildSafeWitnessScript
+func ParseSafeWitnessScript(script []byte, chain byte) (*WitnessScriptAccount, error) {
+	var keys []string
+	var sequence int64
+	tokenizer := txscript.MakeScriptTokenizer(0, script)
+	for tokenizer.Next() {
+		op, data := tokenizer.Opcode(), tokenizer.Data()
+		switch {
+		case len(data) == 33:
+			keys = append(keys, hex.EncodeToString(data))
+		case len(keys) == 3 && sequence == 0 && op >= txscript.OP_1 && op <= txscript.OP_16:
+			sequence = int64(op - (txscript.OP_1 - 1))
+		case len(keys) == 3 && sequence == 0 && len(data) > 0:
+			sequence = parseScriptNum(data)
+		}
+	}
+	if err := tokenizer.Err(); err != nil {
+		return nil, fmt.Errorf("invalid script %x: %v", script, err)
+	}
+	if len(keys) != 3 {
+		return nil, fmt.Errorf("invalid script %x", script)
+	}
+	wsa, err := BuildSafeWitnessScript(keys[0], keys[1], keys[2], sequence, chain)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(wsa.Script, script) {
+		return nil, fmt.Errorf("invalid script %x", script)
+	}
+	return wsa, nil
+}
+
+// VerifySafeWitnessScript checks the address is derived from the script,
+// e.g. the Address and Script of client.Account
+func VerifySafeWitnessScript(addr, script string, chain byte) (*WitnessScriptAccount, error) {
+	b, err := hex.DecodeString(script)
+	if err != nil {
+		return nil, err
+	}
+	wsa, err := ParseSafeWitnessScript(b, chain)
+	if err != nil {
+		return nil, err
+	}
+	if wsa.Address != addr {
+		return nil, fmt.Errorf("invalid address %s %s", addr, wsa.Address)
+	}
+	return wsa, nil
+}
+
+// The script is P2WSH on segwit chains, and P2SH for the others
+func witnessScriptAddress(script []byte, chain byte) (string, error) {
+	cfg, err := common.NetConfig(chain)
+	if err != nil {
+		return "", err
+	}
+	segwit, err := isSegwitChain(chain)
+	if err != nil {
+		return "", err
+	}
+	if !segwit {
+		sh, err := address.NewAddressScriptHash(script, cfg)
+		if err != nil {
+			return "", err
+		}
+		return encodeAddress(sh, chain)
+	}
+	msh := sha256.Sum256(script)
+	wsh, err := address.NewAddressWitnessScriptHash(msh[:], cfg)
+	if err != nil {
+		return "", err
+	}
+	return encodeAddress(wsh, chain)
+}
+
+func parseScriptNum(data []byte) int64 {
+	var num int64
+	for i, b := range data {
+		num |= int64(b) << uint(8*i)
+	}
+	if data[len(data)-1]&0x80 != 0 {
+		num &= ^(int64(0x80) << uint(8*(len(data)-1)))
+		return -num
+	}
+	return num
+}
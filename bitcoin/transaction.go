@@ -29,6 +29,9 @@ type Input struct {
 	Script          []byte
 	Sequence        uint32
 	RouteBackup     bool
+	// the hex of the previous transaction, which is required on chains
+	// without segwit to sign the input
+	RawTransaction string
 }
 
 type Output struct {
@@ -69,9 +72,9 @@ func EstimateTransactionFee(mainInputs []*Input, feeInputs []*Input, outputs []*
 		}
 	}
 
-	estvb := (40 + len(msgTx.TxIn)*300 + (len(msgTx.TxOut)+1)*128) / 4
-	if len(rid) > 0 && len(rid) <= 64 {
-		estvb += len(rid)
+	estvb, err := estimateTransactionSize(msgTx, rid, chain)
+	if err != nil {
+		return 0, err
 	}
 	feeConsumed := fvb * int64(estvb)
 	if feeConsumed > feeSatoshi {
@@ -82,10 +85,6 @@ func EstimateTransactionFee(mainInputs []*Input, feeInputs []*Input, outputs []*
 
 func BuildPartiallySignedTransaction(mainInputs []*Input, outputs []*Output, rid []byte, chain byte) (*PartiallySignedTransaction, error) {
 	msgTx := wire.NewMsgTx(2)
-	cfg, err := common.NetConfig(chain)
-	if err != nil {
-		return nil, err
-	}
 
 	mainAddress, mainSatoshi, err := addInputs(msgTx, mainInputs, chain)
 	if err != nil {
@@ -115,9 +114,9 @@ func BuildPartiallySignedTransaction(mainInputs []*Input, outputs []*Output, rid
 		}
 	}
 
-	estvb := (40 + len(msgTx.TxIn)*300 + (len(msgTx.TxOut)+1)*128) / 4
-	if len(rid) > 0 && len(rid) <= 64 {
-		estvb += len(rid)
+	estvb, err := estimateTransactionSize(msgTx, rid, chain)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(rid) > 0 && len(rid) <= 64 {
@@ -153,12 +152,20 @@ func BuildPartiallySignedTransaction(mainInputs []*Input, outputs []*Output, rid
 		return nil, fmt.Errorf("mempool.CheckTransactionStandard() => %v", err)
 	}
 
+	segwit, err := isSegwitChain(chain)
+	if err != nil {
+		return nil, err
+	}
+	hashType, err := sigHashType(chain)
+	if err != nil {
+		return nil, err
+	}
 	pkt, err := psbt.NewFromUnsignedTx(msgTx)
 	if err != nil {
 		return nil, fmt.Errorf("psbt.NewFromUnsignedTx() => %v", err)
 	}
 	for i, in := range mainInputs {
-		addr, err := address.DecodeAddress(mainAddress, cfg)
+		addr, err := decodeAddress(mainAddress, chain)
 		if err != nil {
 			return nil, err
 		}
@@ -166,12 +173,24 @@ func BuildPartiallySignedTransaction(mainInputs []*Input, outputs []*Output, rid
 		if err != nil {
 			return nil, err
 		}
-		pin := psbt.NewPsbtInput(nil, &wire.TxOut{
-			Value:    in.Satoshi,
-			PkScript: pkScript,
-		})
-		pin.WitnessScript = in.Script
-		pin.SighashType = SigHashType
+		var pin *psbt.PInput
+		if segwit {
+			pin = psbt.NewPsbtInput(nil, &wire.TxOut{
+				Value:    in.Satoshi,
+				PkScript: pkScript,
+			})
+			pin.WitnessScript = in.Script
+		} else {
+			prev, err := decodePreviousTransaction(in, pkScript)
+			if err != nil {
+				return nil, err
+			}
+			pin = psbt.NewPsbtInput(prev, nil)
+			if !txscript.IsPayToPubKeyHash(pkScript) {
+				pin.RedeemScript = in.Script
+			}
+		}
+		pin.SighashType = hashType
 		if !pin.IsSane() {
 			return nil, fmt.Errorf("!pin.IsSane")
 		}
@@ -187,6 +206,28 @@ func BuildPartiallySignedTransaction(mainInputs []*Input, outputs []*Output, rid
 	}, nil
 }
 
+// decodePreviousTransaction checks the previous transaction has the output
+// spent by the input
+func decodePreviousTransaction(in *Input, pkScript []byte) (*wire.MsgTx, error) {
+	b, err := hex.DecodeString(in.RawTransaction)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid input %s:%d raw transaction", in.TransactionHash, in.Index)
+	}
+	var prev wire.MsgTx
+	err = prev.DeserializeNoWitness(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("input %s:%d raw transaction => %v", in.TransactionHash, in.Index, err)
+	}
+	if prev.TxHash().String() != in.TransactionHash || int(in.Index) >= len(prev.TxOut) {
+		return nil, fmt.Errorf("input %s:%d raw transaction %s", in.TransactionHash, in.Index, prev.TxHash())
+	}
+	out := prev.TxOut[in.Index]
+	if out.Value != in.Satoshi || !bytes.Equal(out.PkScript, pkScript) {
+		return nil, fmt.Errorf("input %s:%d raw transaction output mismatch", in.TransactionHash, in.Index)
+	}
+	return &prev, nil
+}
+
 func addInputs(tx *wire.MsgTx, inputs []*Input, chain byte) (string, int64, error) {
 	var address string
 	var inputSatoshi int64
@@ -211,6 +252,13 @@ func addInput(tx *wire.MsgTx, in *Input, chain byte) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	segwit, err := isSegwitChain(chain)
+	if err != nil {
+		return "", err
+	}
+	if !segwit {
+		return addLegacyInput(tx, in, chain)
+	}
 	var addr string
 	hash, err := chainhash.NewHashFromStr(in.TransactionHash)
 	if err != nil {
@@ -272,12 +320,69 @@ func addInput(tx *wire.MsgTx, in *Input, chain byte) (string, error) {
 	return addr, nil
 }
 
-func addOutput(tx *wire.MsgTx, receiver string, satoshi int64, chain byte) error {
+// Chains without segwit use P2PKH for accountant and P2SH for multisig
+func addLegacyInput(tx *wire.MsgTx, in *Input, chain byte) (string, error) {
 	cfg, err := common.NetConfig(chain)
 	if err != nil {
-		return err
+		return "", err
+	}
+	hash, err := chainhash.NewHashFromStr(in.TransactionHash)
+	if err != nil {
+		return "", err
+	}
+	txIn := &wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  *hash,
+			Index: in.Index,
+		},
 	}
-	addr, err := address.DecodeAddress(receiver, cfg)
+	typ, err := checkScriptType(in.Script)
+	if err != nil {
+		return "", err
+	}
+	if in.RouteBackup {
+		typ = InputTypeP2WSHMultisigObserverSigner
+	}
+	var addr address.Address
+	switch typ {
+	case InputTypeP2WPKHAccoutant:
+		pkh, err := address.NewAddressPubKeyHash(address.Hash160(in.Script), cfg)
+		if err != nil {
+			return "", err
+		}
+		script, err := txscript.PayToAddrScript(pkh)
+		if err != nil {
+			return "", err
+		}
+		in.Script = script
+		addr = pkh
+		txIn.Sequence = MaxTransactionSequence
+	case InputTypeP2WSHMultisigHolderSigner:
+		sh, err := address.NewAddressScriptHash(in.Script, cfg)
+		if err != nil {
+			return "", err
+		}
+		addr = sh
+		txIn.Sequence = MaxTransactionSequence
+	case InputTypeP2WSHMultisigObserverSigner:
+		sh, err := address.NewAddressScriptHash(in.Script, cfg)
+		if err != nil {
+			return "", err
+		}
+		addr = sh
+		txIn.Sequence = in.Sequence
+	default:
+		return "", fmt.Errorf("invalid input type %d", typ)
+	}
+	if txIn.Sequence == 0 {
+		return "", fmt.Errorf("invalid sequence %d", in.Sequence)
+	}
+	tx.AddTxIn(txIn)
+	return encodeAddress(addr, chain)
+}
+
+func addOutput(tx *wire.MsgTx, receiver string, satoshi int64, chain byte) error {
+	addr, err := decodeAddress(receiver, chain)
 	if err != nil {
 		return err
 	}
@@ -289,6 +394,21 @@ func addOutput(tx *wire.MsgTx, receiver string, satoshi int64, chain byte) error
 	return nil
 }
 
+func estimateTransactionSize(tx *wire.MsgTx, rid []byte, chain byte) (int, error) {
+	segwit, err := isSegwitChain(chain)
+	if err != nil {
+		return 0, err
+	}
+	estvb := (40 + len(tx.TxIn)*300 + (len(tx.TxOut)+1)*128) / 4
+	if !segwit {
+		estvb = 10 + len(tx.TxIn)*400 + (len(tx.TxOut)+1)*34
+	}
+	if len(rid) > 0 && len(rid) <= 64 {
+		estvb += len(rid)
+	}
+	return estvb, nil
+}
+
 func checkScriptType(script []byte) (int, error) {
 	if len(script) == 33 {
 		return InputTypeP2WPKHAccoutant, nil
@@ -334,10 +454,59 @@ func UnmarshalPartiallySignedTransaction(b []byte) (*PartiallySignedTransaction,
 func (psbt *PartiallySignedTransaction) SigHash(idx int) ([]byte, error) {
 	tx := psbt.UnsignedTx
 	pin := psbt.Inputs[idx]
-	satoshi := pin.WitnessUtxo.Value
-	pof := txscript.NewCannedPrevOutputFetcher(pin.WitnessScript, satoshi)
-	tsh := txscript.NewTxSigHashes(tx, pof)
-	return txscript.CalcWitnessSigHash(pin.WitnessScript, tsh, SigHashType, tx, idx, satoshi)
+	hashType := pin.SighashType
+	if hashType == 0 {
+		hashType = SigHashType
+	}
+	utxo, err := psbt.inputUtxo(idx)
+	if err != nil {
+		return nil, err
+	}
+	script := psbt.inputScript(idx, utxo)
+	switch {
+	case len(pin.WitnessScript) > 0:
+		pof := txscript.NewCannedPrevOutputFetcher(script, utxo.Value)
+		tsh := txscript.NewTxSigHashes(tx, pof)
+		return txscript.CalcWitnessSigHash(script, tsh, hashType, tx, idx, utxo.Value)
+	case len(script) > 0 && hashType&common.SigHashForkId != 0:
+		// Bitcoin Cash signs the BIP143 digest with the fork id hash type
+		pof := txscript.NewCannedPrevOutputFetcher(script, utxo.Value)
+		tsh := txscript.NewTxSigHashes(tx, pof)
+		return txscript.CalcWitnessSigHash(script, tsh, hashType, tx, idx, utxo.Value)
+	case len(script) > 0:
+		return txscript.CalcSignatureHash(script, hashType, tx, idx)
+	default:
+		return nil, fmt.Errorf("invalid input %d without script", idx)
+	}
+}
+
+// inputUtxo returns the spent output, which is in the full previous
+// transaction on chains without segwit
+func (raw *PartiallySignedTransaction) inputUtxo(idx int) (*wire.TxOut, error) {
+	pin := raw.Inputs[idx]
+	if pin.WitnessUtxo != nil {
+		return pin.WitnessUtxo, nil
+	}
+	prev, op := pin.NonWitnessUtxo, raw.UnsignedTx.TxIn[idx].PreviousOutPoint
+	if prev == nil || prev.TxHash() != op.Hash || int(op.Index) >= len(prev.TxOut) {
+		return nil, fmt.Errorf("invalid input %d utxo", idx)
+	}
+	return prev.TxOut[op.Index], nil
+}
+
+// inputScript returns the script signed by the input, and the P2PKH input
+// without a redeem script signs the script of the spent output
+func (raw *PartiallySignedTransaction) inputScript(idx int, utxo *wire.TxOut) []byte {
+	pin := raw.Inputs[idx]
+	switch {
+	case len(pin.WitnessScript) > 0:
+		return pin.WitnessScript
+	case len(pin.RedeemScript) > 0:
+		return pin.RedeemScript
+	case txscript.IsPayToPubKeyHash(utxo.PkScript):
+		return utxo.PkScript
+	}
+	return nil
 }
 
 func MarshalWiredTransaction(msgTx *wire.MsgTx, encoding wire.MessageEncoding, chain byte) ([]byte, error) {
@@ -408,10 +577,18 @@ func SignTx(rawStr, privateStr string, chain byte) (string, error) {
 			return "", err
 		}
 		sig := ecdsa.Sign(holder, hash).Serialize()
-		hpsbt.Packet.Inputs[idx].PartialSigs = []*psbt.PartialSig{{
-			PubKey:    holder.PubKey().SerializeCompressed(),
+		pub := holder.PubKey().SerializeCompressed()
+		pin := &hpsbt.Packet.Inputs[idx]
+		var sigs []*psbt.PartialSig
+		for _, ps := range pin.PartialSigs {
+			if !bytes.Equal(ps.PubKey, pub) {
+				sigs = append(sigs, ps)
+			}
+		}
+		pin.PartialSigs = append(sigs, &psbt.PartialSig{
+			PubKey:    pub,
 			Signature: sig,
-		}}
+		})
 	}
 	raw, err := hpsbt.Marshal()
 	if err != nil {
@@ -2,41 +2,372 @@ package common
 
 import (
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/chaincfg/v2"
+	"github.com/btcsuite/btcd/txscript/v2"
+	"github.com/btcsuite/btcd/wire/v2"
 )
 
 const (
-	ChainBitcoin  = 1
-	ChainLitecoin = 5
+	ChainBitcoin     = 1
+	ChainEthereum    = 2
+	ChainMixinKernel = 3
+	ChainMVM         = 4
+	ChainLitecoin    = 5
+	ChainPolygon     = 6
+	ChainDogecoin    = 7
+	ChainBitcoinCash = 8
+	ChainArbitrum    = 9
+	ChainOptimism    = 10
+	ChainBase        = 11
+	ChainBNB         = 12
+	ChainAvalanche   = 13
+
+	CurveSecp256k1ECDSABitcoin     = 1
+	CurveSecp256k1ECDSAEthereum    = 2
+	CurveSecp256k1ECDSALitecoin    = 100 + CurveSecp256k1ECDSABitcoin
+	CurveSecp256k1ECDSAMVM         = 100 + CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSAPolygon     = 110 + CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSADogecoin    = 110 + CurveSecp256k1ECDSABitcoin
+	CurveSecp256k1ECDSABitcoinCash = 120 + CurveSecp256k1ECDSABitcoin
+	CurveSecp256k1ECDSAArbitrum    = 120 + CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSAOptimism    = 130 + CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSABase        = 140 + CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSABNB         = 150 + CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSAAvalanche   = 160 + CurveSecp256k1ECDSAEthereum
+
+	FamilyBitcoin  = 1
+	FamilyEthereum = 2
+
+	// SigHashForkId is required by Bitcoin Cash to sign with BIP143 digest
+	SigHashForkId txscript.SigHashType = 0x40
+)
+
+// ChainInfo describes everything the SDK needs to know about a safe chain,
+// Params, Networks, ProtocolVersion, MessagePrefix and SigHashType are only
+// used by Bitcoin like chains, and EvmChainId is only used by Ethereum like
+// chains. Params are the parameters of the selected Network, which is mainnet
+// by default, and the chain is considered without segwit if the params have
+// no Bech32HRPSegwit.
+type ChainInfo struct {
+	Chain           byte
+	AssetId         string
+	Name            string
+	Symbol          string
+	Curve           uint8
+	Family          uint8
+	EvmChainId      int64
+	Network         string
+	Params          *chaincfg.Params
+	Networks        map[string]*chaincfg.Params
+	ProtocolVersion uint32
+	MessagePrefix   string
+	SigHashType     txscript.SigHashType
+	BlockTime       time.Duration
+	Dust            int64
+	Precision       int32
+}
+
+var (
+	chainsLock sync.RWMutex
+	chains     = make(map[byte]*ChainInfo)
 )
 
 func init() {
-	ltcParams, _ := NetConfig(ChainLitecoin)
-	err := chaincfg.Register(ltcParams)
+	for _, c := range []*ChainInfo{{
+		Chain:   ChainBitcoin,
+		AssetId: "c6d0c728-2624-429b-8e0d-d9d19b6592fa",
+		Name:    "Bitcoin",
+		Symbol:  "BTC",
+		Curve:   CurveSecp256k1ECDSABitcoin,
+		Family:  FamilyBitcoin,
+		Params:  &chaincfg.MainNetParams,
+		Networks: map[string]*chaincfg.Params{
+			NetworkTestnet: &chaincfg.TestNet3Params,
+			NetworkSignet:  &chaincfg.SigNetParams,
+			NetworkRegtest: &chaincfg.RegressionNetParams,
+		},
+		ProtocolVersion: wire.ProtocolVersion,
+		MessagePrefix:   "Bitcoin Signed Message:\n",
+		SigHashType:     txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+		BlockTime:       10 * time.Minute,
+		Dust:            1000,
+		Precision:       8,
+	}, {
+		Chain:   ChainLitecoin,
+		AssetId: "76c802a2-7c88-447f-a93e-c29c9e5dd9c8",
+		Name:    "Litecoin",
+		Symbol:  "LTC",
+		Curve:   CurveSecp256k1ECDSALitecoin,
+		Family:  FamilyBitcoin,
+		Params:  &litecoinMainNetParams,
+		Networks: map[string]*chaincfg.Params{
+			NetworkTestnet: &litecoinTestNetParams,
+			NetworkRegtest: &litecoinRegressionNetParams,
+		},
+		ProtocolVersion: 70015,
+		MessagePrefix:   "Litecoin Signed Message:\n",
+		SigHashType:     txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+		BlockTime:       150 * time.Second,
+		Dust:            10000,
+		Precision:       8,
+	}, {
+		Chain:   ChainDogecoin,
+		AssetId: "6770a1e5-6086-44d5-b60f-545f9d9e8ffd",
+		Name:    "Dogecoin",
+		Symbol:  "DOGE",
+		Curve:   CurveSecp256k1ECDSADogecoin,
+		Family:  FamilyBitcoin,
+		Params:  &dogecoinMainNetParams,
+		Networks: map[string]*chaincfg.Params{
+			NetworkTestnet: &dogecoinTestNetParams,
+			NetworkRegtest: &dogecoinRegressionNetParams,
+		},
+		ProtocolVersion: 70015,
+		MessagePrefix:   "Dogecoin Signed Message:\n",
+		SigHashType:     txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+		BlockTime:       time.Minute,
+		Dust:            1000000,
+		Precision:       8,
+	}, {
+		Chain:   ChainBitcoinCash,
+		AssetId: "fd11b6e3-0b87-41f1-a41f-f0e9b49e5bf0",
+		Name:    "Bitcoin Cash",
+		Symbol:  "BCH",
+		Curve:   CurveSecp256k1ECDSABitcoinCash,
+		Family:  FamilyBitcoin,
+		Params:  &bitcoinCashMainNetParams,
+		Networks: map[string]*chaincfg.Params{
+			NetworkTestnet: &bitcoinCashTestNetParams,
+			NetworkRegtest: &bitcoinCashRegressionNetParams,
+		},
+		ProtocolVersion: 70016,
+		MessagePrefix:   "Bitcoin Signed Message:\n",
+		SigHashType:     txscript.SigHashAll | txscript.SigHashAnyOneCanPay | SigHashForkId,
+		BlockTime:       10 * time.Minute,
+		Dust:            1000,
+		Precision:       8,
+	}, {
+		Chain:      ChainEthereum,
+		AssetId:    "43d61dcd-e413-450d-80b8-101d5e903357",
+		Name:       "Ether",
+		Symbol:     "ETH",
+		Curve:      CurveSecp256k1ECDSAEthereum,
+		Family:     FamilyEthereum,
+		EvmChainId: 1,
+		Dust:       100000000000000,
+		Precision:  18,
+	}, {
+		Chain:      ChainMVM,
+		AssetId:    "a0ffd769-5850-4b48-9651-d2ae44a3e64d",
+		Name:       "Ether",
+		Symbol:     "ETH",
+		Curve:      CurveSecp256k1ECDSAMVM,
+		Family:     FamilyEthereum,
+		EvmChainId: 73927,
+		Dust:       100000000000000,
+		Precision:  18,
+	}, {
+		Chain:      ChainPolygon,
+		AssetId:    "b7938396-3f94-4e0a-9179-d3440718156f",
+		Name:       "Polygon",
+		Symbol:     "MATIC",
+		Curve:      CurveSecp256k1ECDSAPolygon,
+		Family:     FamilyEthereum,
+		EvmChainId: 137,
+		Dust:       100000000000000,
+		Precision:  18,
+	}, {
+		Chain:      ChainArbitrum,
+		AssetId:    "8c590110-1abc-3697-84f2-05214e6516aa",
+		Name:       "Ether",
+		Symbol:     "ETH",
+		Curve:      CurveSecp256k1ECDSAArbitrum,
+		Family:     FamilyEthereum,
+		EvmChainId: 42161,
+		Dust:       100000000000000,
+		Precision:  18,
+	}, {
+		Chain:      ChainOptimism,
+		AssetId:    "62d5b01f-24ee-4c96-8214-8e04981d05f2",
+		Name:       "Ether",
+		Symbol:     "ETH",
+		Curve:      CurveSecp256k1ECDSAOptimism,
+		Family:     FamilyEthereum,
+		EvmChainId: 10,
+		Dust:       100000000000000,
+		Precision:  18,
+	}, {
+		Chain:      ChainBase,
+		AssetId:    "3fb612c5-6844-3979-ae4a-5a84e79da870",
+		Name:       "Ether",
+		Symbol:     "ETH",
+		Curve:      CurveSecp256k1ECDSABase,
+		Family:     FamilyEthereum,
+		EvmChainId: 8453,
+		Dust:       100000000000000,
+		Precision:  18,
+	}, {
+		Chain:      ChainBNB,
+		AssetId:    "1949e683-6a08-49e2-b087-d6b72398588f",
+		Name:       "BNB",
+		Symbol:     "BNB",
+		Curve:      CurveSecp256k1ECDSABNB,
+		Family:     FamilyEthereum,
+		EvmChainId: 56,
+		Dust:       100000000000000,
+		Precision:  18,
+	}, {
+		Chain:      ChainAvalanche,
+		AssetId:    "cbc77539-0a20-4666-8c8a-4ded62b36f0a",
+		Name:       "Avalanche",
+		Symbol:     "AVAX",
+		Curve:      CurveSecp256k1ECDSAAvalanche,
+		Family:     FamilyEthereum,
+		EvmChainId: 43114,
+		Dust:       100000000000000,
+		Precision:  18,
+	}} {
+		err := RegisterChain(c)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterChain adds a chain to the registry, it should be called in the
+// init of applications, and a chain could not be registered twice
+func RegisterChain(info *ChainInfo) error {
+	switch info.Family {
+	case FamilyBitcoin:
+		if info.Params == nil || info.ProtocolVersion == 0 {
+			return fmt.Errorf("invalid bitcoin chain %d params", info.Chain)
+		}
+	case FamilyEthereum:
+		if info.EvmChainId <= 0 {
+			return fmt.Errorf("invalid ethereum chain %d id %d", info.Chain, info.EvmChainId)
+		}
+	default:
+		return fmt.Errorf("invalid chain %d family %d", info.Chain, info.Family)
+	}
+	if info.Family == FamilyBitcoin && info.SigHashType == 0 {
+		return fmt.Errorf("invalid bitcoin chain %d sighash type", info.Chain)
+	}
+	if info.Curve == 0 || info.AssetId == "" || info.Precision <= 0 {
+		return fmt.Errorf("invalid chain %d info", info.Chain)
+	}
+
+	chainsLock.Lock()
+	defer chainsLock.Unlock()
+
+	if chains[info.Chain] != nil {
+		return fmt.Errorf("duplicated chain %d", info.Chain)
+	}
+	for _, c := range chains {
+		if c.Curve == info.Curve {
+			return fmt.Errorf("duplicated chain %d curve %d", info.Chain, info.Curve)
+		}
+		if c.EvmChainId > 0 && c.EvmChainId == info.EvmChainId {
+			return fmt.Errorf("duplicated chain %d evm chain id %d", info.Chain, info.EvmChainId)
+		}
+	}
+	c := *info
+	if c.Family == FamilyBitcoin {
+		c.Networks = make(map[string]*chaincfg.Params)
+		for n, p := range info.Networks {
+			c.Networks[n] = p
+		}
+		if c.Network == "" {
+			c.Network = NetworkMainnet
+		}
+		c.Networks[c.Network] = c.Params
+		for _, p := range c.Networks {
+			err := registerNetParams(p)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	chains[info.Chain] = &c
+	return nil
+}
+
+func ReadChain(chain byte) (*ChainInfo, error) {
+	chainsLock.RLock()
+	defer chainsLock.RUnlock()
+
+	c := chains[chain]
+	if c == nil {
+		return nil, fmt.Errorf("invalid chain %d", chain)
+	}
+	info := *c
+	return &info, nil
+}
+
+func ReadChainByEvmChainId(id int64) (*ChainInfo, error) {
+	chainsLock.RLock()
+	defer chainsLock.RUnlock()
+
+	for _, c := range chains {
+		if c.Family == FamilyEthereum && c.EvmChainId == id {
+			info := *c
+			return &info, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid evm chain id %d", id)
+}
+
+func ReadChainByAssetId(assetId string) (*ChainInfo, error) {
+	chainsLock.RLock()
+	defer chainsLock.RUnlock()
+
+	for _, c := range chains {
+		if c.AssetId == assetId {
+			info := *c
+			return &info, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid chain asset id %s", assetId)
+}
+
+func ChainFamily(chain byte) uint8 {
+	c, err := ReadChain(chain)
 	if err != nil {
-		panic(err)
+		return 0
 	}
+	return c.Family
 }
 
 func NetConfig(chain byte) (*chaincfg.Params, error) {
-	switch chain {
-	case ChainBitcoin:
-		return &chaincfg.MainNetParams, nil
-	case ChainLitecoin:
-		return &chaincfg.Params{
-			Net:             0xdbb6c0fb,
-			Bech32HRPSegwit: "ltc",
-
-			PubKeyHashAddrID:        0x30,
-			ScriptHashAddrID:        0x32,
-			WitnessPubKeyHashAddrID: 0x06,
-			WitnessScriptHashAddrID: 0x0A,
-
-			HDPublicKeyID:  [4]byte{0x01, 0x9d, 0xa4, 0x64},
-			HDPrivateKeyID: [4]byte{0x01, 0x9d, 0x9c, 0xfe},
-		}, nil
-	default:
+	c, err := ReadChain(chain)
+	if err != nil {
+		return nil, err
+	}
+	if c.Params == nil {
 		return nil, fmt.Errorf("invalid chain %d", chain)
 	}
+	return c.Params, nil
+}
+
+// The btcd networks are registered in the init of chaincfg
+var defaultNetParams = []*chaincfg.Params{
+	&chaincfg.MainNetParams,
+	&chaincfg.TestNet3Params,
+	&chaincfg.TestNet4Params,
+	&chaincfg.RegressionNetParams,
+	&chaincfg.SimNetParams,
+}
+
+func registerNetParams(params *chaincfg.Params) error {
+	if slices.Contains(defaultNetParams, params) {
+		return nil
+	}
+	err := chaincfg.Register(params)
+	if err != nil {
+		return fmt.Errorf("register %s net %x => %v", params.Name, uint32(params.Net), err)
+	}
+	return nil
 }
@@ -0,0 +1,194 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type rpcEndpoint[C any] struct {
+	client C
+	height int64
+	err    error
+}
+
+// RPCPool keeps the health of several endpoints of the same chain. An endpoint
+// is unhealthy after an endpoint error, or when its height lags behind the
+// highest one by more than MaxHeightLag, until the next health check.
+type RPCPool[C any] struct {
+	mutex     sync.Mutex
+	endpoints []*rpcEndpoint[C]
+	height    func(context.Context, C) (int64, error)
+	unhealthy func(error) bool
+	checked   time.Time
+
+	MaxHeightLag   int64
+	HealthInterval time.Duration
+	// each call to an endpoint is canceled after Timeout, so a hung endpoint
+	// doesn't block the failover or the health check
+	Timeout time.Duration
+}
+
+// NewRPCPool prefers the clients in order among the healthy ones, and the
+// height function is used for health checks. The unhealthy function tells the
+// endpoint errors, e.g. network or server errors, from the errors of the call
+// itself, e.g. a reverted contract call, which don't mark the endpoint.
+func NewRPCPool[C any](clients []C, height func(context.Context, C) (int64, error), unhealthy func(error) bool) (*RPCPool[C], error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no rpc endpoints")
+	}
+	p := &RPCPool[C]{
+		height:         height,
+		unhealthy:      unhealthy,
+		MaxHeightLag:   3,
+		HealthInterval: time.Minute,
+		Timeout:        30 * time.Second,
+	}
+	for _, c := range clients {
+		p.endpoints = append(p.endpoints, &rpcEndpoint[C]{client: c})
+	}
+	return p, nil
+}
+
+// CheckHealth queries the heights of all endpoints, and returns the highest
+// one among the endpoints not lagging
+func (p *RPCPool[C]) CheckHealth(ctx context.Context) (int64, error) {
+	heights := make([]int64, len(p.endpoints))
+	errs := make([]error, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			heights[i], errs[i] = call(ctx, p, e.client, p.height)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	var tip int64
+	for i := range p.endpoints {
+		if errs[i] == nil {
+			tip = max(tip, heights[i])
+		}
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.checked = time.Now()
+	var healthy int
+	for i, e := range p.endpoints {
+		e.height, e.err = heights[i], errs[i]
+		if e.err == nil && tip-e.height > p.MaxHeightLag {
+			e.err = fmt.Errorf("rpc height %d lags behind %d", e.height, tip)
+		}
+		if e.err == nil {
+			healthy += 1
+		}
+	}
+	if healthy == 0 {
+		return 0, fmt.Errorf("no healthy rpc endpoints %v", errs)
+	}
+	return tip, nil
+}
+
+// clients returns the healthy endpoints first, and checks the health when
+// the last check is older than HealthInterval
+func (p *RPCPool[C]) clients(ctx context.Context) []*rpcEndpoint[C] {
+	p.mutex.Lock()
+	stale := time.Since(p.checked) > p.HealthInterval
+	p.mutex.Unlock()
+	if stale {
+		p.CheckHealth(ctx)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	endpoints := slices.Clone(p.endpoints)
+	slices.SortStableFunc(endpoints, func(a, b *rpcEndpoint[C]) int {
+		switch {
+		case a.err == nil && b.err != nil:
+			return -1
+		case a.err != nil && b.err == nil:
+			return 1
+		}
+		return 0
+	})
+	return endpoints
+}
+
+// fail ignores the errors of the call itself, and those after the context
+// of the caller is done
+func (p *RPCPool[C]) fail(ctx context.Context, e *rpcEndpoint[C], err error) {
+	if ctx.Err() != nil || !p.unhealthy(err) {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	e.err = err
+}
+
+func call[C, T any](ctx context.Context, p *RPCPool[C], c C, fn func(context.Context, C) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+	return fn(ctx, c)
+}
+
+// RPCFailover calls fn with the endpoints in order until one succeeds, and
+// marks those failed with endpoint errors unhealthy
+func RPCFailover[C, T any](ctx context.Context, p *RPCPool[C], fn func(context.Context, C) (T, error)) (T, error) {
+	var res T
+	var err error
+	for _, e := range p.clients(ctx) {
+		res, err = call(ctx, p, e.client, fn)
+		if err == nil {
+			return res, nil
+		}
+		p.fail(ctx, e, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return res, err
+}
+
+// RPCQuorum calls fn with all endpoints, and returns the result only when
+// the same one, compared by the JSON encoding, is from a majority of them.
+// The lagging endpoints are included, so fn should read at a fixed height.
+func RPCQuorum[C, T any](ctx context.Context, p *RPCPool[C], fn func(context.Context, C) (T, error)) (T, error) {
+	endpoints := p.clients(ctx)
+	results := make([]T, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = call(ctx, p, e.client, fn)
+		}()
+	}
+	wg.Wait()
+
+	votes := make(map[string]int)
+	for i, e := range endpoints {
+		if errs[i] != nil {
+			p.fail(ctx, e, errs[i])
+			continue
+		}
+		key, err := json.Marshal(results[i])
+		if err != nil {
+			var res T
+			return res, fmt.Errorf("json.Marshal(%v) => %v", results[i], err)
+		}
+		votes[string(key)] += 1
+		if votes[string(key)] > len(endpoints)/2 {
+			return results[i], nil
+		}
+	}
+	var res T
+	return res, fmt.Errorf("no rpc quorum of %d endpoints %v", len(endpoints), errs)
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/v2"
+)
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+	NetworkSignet  = "signet"
+	NetworkRegtest = "regtest"
+)
+
+var (
+	litecoinMainNetParams = chaincfg.Params{
+		Name:             "litecoin",
+		Net:              0xdbb6c0fb,
+		Bech32HRPSegwit:  "ltc",
+		CoinbaseMaturity: 100,
+
+		PubKeyHashAddrID:        0x30,
+		ScriptHashAddrID:        0x32,
+		WitnessPubKeyHashAddrID: 0x06,
+		WitnessScriptHashAddrID: 0x0A,
+
+		HDPublicKeyID:  [4]byte{0x01, 0x9d, 0xa4, 0x64},
+		HDPrivateKeyID: [4]byte{0x01, 0x9d, 0x9c, 0xfe},
+	}
+
+	litecoinTestNetParams = chaincfg.Params{
+		Name:             "litecoin-testnet4",
+		Net:              0xf1c8d2fd,
+		Bech32HRPSegwit:  "tltc",
+		CoinbaseMaturity: 100,
+
+		PubKeyHashAddrID:        0x6f,
+		ScriptHashAddrID:        0x3a,
+		WitnessPubKeyHashAddrID: 0x52,
+		WitnessScriptHashAddrID: 0x31,
+
+		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94},
+	}
+
+	// The Net of the regtest params only keys the chaincfg registry, so it
+	// must differ from the Bitcoin regtest to register the address prefixes
+	litecoinRegressionNetParams = chaincfg.Params{
+		Name:             "litecoin-regtest",
+		Net:              0xdab5bffb,
+		Bech32HRPSegwit:  "rltc",
+		CoinbaseMaturity: 100,
+
+		PubKeyHashAddrID:        0x6f,
+		ScriptHashAddrID:        0x3a,
+		WitnessPubKeyHashAddrID: 0x52,
+		WitnessScriptHashAddrID: 0x31,
+
+		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94},
+	}
+
+	dogecoinMainNetParams = chaincfg.Params{
+		Name:             "dogecoin",
+		Net:              0xc0c0c0c0,
+		CoinbaseMaturity: 240,
+
+		PubKeyHashAddrID: 0x1e,
+		ScriptHashAddrID: 0x16,
+		PrivateKeyID:     0x9e,
+
+		HDPublicKeyID:  [4]byte{0x02, 0xfa, 0xca, 0xfd},
+		HDPrivateKeyID: [4]byte{0x02, 0xfa, 0xc3, 0x98},
+	}
+
+	dogecoinTestNetParams = chaincfg.Params{
+		Name:             "dogecoin-testnet",
+		Net:              0xdcb7c1fc,
+		CoinbaseMaturity: 240,
+
+		PubKeyHashAddrID: 0x71,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xf1,
+
+		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94},
+	}
+
+	dogecoinRegressionNetParams = chaincfg.Params{
+		Name:             "dogecoin-regtest",
+		Net:              0xdab5bffc,
+		CoinbaseMaturity: 60,
+
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+
+		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94},
+	}
+
+	bitcoinCashMainNetParams = chaincfg.Params{
+		Name:             "bitcoincash",
+		Net:              0xe8f3e1e3,
+		CoinbaseMaturity: 100,
+
+		PubKeyHashAddrID: 0x00,
+		ScriptHashAddrID: 0x05,
+		PrivateKeyID:     0x80,
+
+		HDPublicKeyID:  [4]byte{0x04, 0x88, 0xb2, 0x1e},
+		HDPrivateKeyID: [4]byte{0x04, 0x88, 0xad, 0xe4},
+	}
+
+	bitcoinCashTestNetParams = chaincfg.Params{
+		Name:             "bchtest",
+		Net:              0xf4f3e5f4,
+		CoinbaseMaturity: 100,
+
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+
+		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94},
+	}
+
+	bitcoinCashRegressionNetParams = chaincfg.Params{
+		Name:             "bchreg",
+		Net:              0xfabfb5da,
+		CoinbaseMaturity: 100,
+
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+
+		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94},
+	}
+)
+
+// SetChainNetwork selects the network of a Bitcoin like chain, all address
+// parsing and transaction building of the chain will use the network params
+func SetChainNetwork(chain byte, network string) error {
+	chainsLock.Lock()
+	defer chainsLock.Unlock()
+
+	c := chains[chain]
+	if c == nil || c.Family != FamilyBitcoin {
+		return fmt.Errorf("invalid chain %d", chain)
+	}
+	params := c.Networks[network]
+	if params == nil {
+		return fmt.Errorf("invalid chain %d network %s", chain, network)
+	}
+	c.Network, c.Params = network, params
+	return nil
+}
+
+// SetNetwork selects the network for all Bitcoin like chains, and it fails
+// without any change if some chain doesn't support the network. The signet
+// is only registered for Bitcoin, so use SetChainNetwork to select it.
+func SetNetwork(network string) error {
+	if network == NetworkSignet {
+		return fmt.Errorf("network %s is only supported by chain %d", network, ChainBitcoin)
+	}
+
+	chainsLock.Lock()
+	defer chainsLock.Unlock()
+
+	for _, c := range chains {
+		if c.Family == FamilyBitcoin && c.Networks[network] == nil {
+			return fmt.Errorf("invalid chain %d network %s", c.Chain, network)
+		}
+	}
+	for _, c := range chains {
+		if c.Family == FamilyBitcoin {
+			c.Network, c.Params = network, c.Networks[network]
+		}
+	}
+	return nil
+}
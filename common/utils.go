@@ -3,6 +3,8 @@ package common
 import (
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"sync"
 
 	"github.com/gofrs/uuid/v5"
 )
@@ -25,3 +27,28 @@ func EncodeMixinExtra(appId, memo string) string {
 	data = append(data, []byte(memo)...)
 	return base64.RawURLEncoding.EncodeToString(data)
 }
+
+// ForEachBatch splits total items into batches of size, then calls fn with
+// the range of each batch from at most workers goroutines
+func ForEachBatch(total, size, workers int, fn func(start, end int)) error {
+	if size < 1 || workers < 1 {
+		return fmt.Errorf("invalid batch size %d workers %d", size, workers)
+	}
+	batches := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, (total+size-1)/size) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range batches {
+				fn(start, min(start+size, total))
+			}
+		}()
+	}
+	for start := 0; start < total; start += size {
+		batches <- start
+	}
+	close(batches)
+	wg.Wait()
+	return nil
+}
@@ -8,24 +8,25 @@ import (
 	"strings"
 	"time"
 
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
 	"github.com/btcsuite/btcd/btcutil/v2/hdkeychain"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-func GetSafeAccountGuard(rpc, address string) (string, error) {
-	conn, abi, err := safeInit(rpc, address)
+func (c *RPCClient) GetSafeAccountGuard(ctx context.Context, address string) (string, error) {
+	abi, err := c.safeContract(address)
 	if err != nil {
 		return "", err
 	}
-	defer conn.Close()
 
 	bGuardOffet, err := hex.DecodeString(guardStorageSlot[2:])
 	if err != nil {
 		return "", err
 	}
-	bGuard, err := abi.GetStorageAt(nil, new(big.Int).SetBytes(bGuardOffet), new(big.Int).SetInt64(1))
+	opt := &bind.CallOpts{Context: ctx}
+	bGuard, err := abi.GetStorageAt(opt, new(big.Int).SetBytes(bGuardOffet), new(big.Int).SetInt64(1))
 	if err != nil {
 		if strings.Contains(err.Error(), "no contract code at given address") {
 			return "", nil
@@ -36,8 +37,8 @@ func GetSafeAccountGuard(rpc, address string) (string, error) {
 	return guardAddress.Hex(), nil
 }
 
-func GetSafeLastTxTime(rpc, address string) (time.Time, error) {
-	guardAddress, err := GetSafeAccountGuard(rpc, address)
+func (c *RPCClient) GetSafeLastTxTime(ctx context.Context, address string) (time.Time, error) {
+	guardAddress, err := c.GetSafeAccountGuard(ctx, address)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -46,14 +47,13 @@ func GetSafeLastTxTime(rpc, address string) (time.Time, error) {
 		panic(fmt.Errorf("safe %s is not deployed or guard is not enabled", address))
 	}
 
-	conn, abi, err := guardInit(rpc, guardAddress)
+	abi, err := c.guardContract(guardAddress)
 	if err != nil {
 		return time.Time{}, err
 	}
-	defer conn.Close()
 
 	addr := common.HexToAddress(address)
-	timestamp, err := abi.SafeLastTxTime(nil, addr)
+	timestamp, err := abi.SafeLastTxTime(&bind.CallOpts{Context: ctx}, addr)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -61,20 +61,112 @@ func GetSafeLastTxTime(rpc, address string) (time.Time, error) {
 	return t, nil
 }
 
-func GetOwners(rpc, address string) ([]common.Address, error) {
-	conn, abi, err := safeInit(rpc, address)
+func (c *RPCClient) GetOwners(ctx context.Context, address string) ([]common.Address, error) {
+	abi, err := c.safeContract(address)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
-	os, err := abi.GetOwners(nil)
+	os, err := abi.GetOwners(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return nil, err
 	}
 	return os, nil
 }
 
+func (c *RPCClient) GetThreshold(ctx context.Context, address string) (int64, error) {
+	abi, err := c.safeContract(address)
+	if err != nil {
+		return 0, err
+	}
+
+	threshold, err := abi.GetThreshold(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, err
+	}
+	return threshold.Int64(), nil
+}
+
+func (c *RPCClient) GetERC20Allowance(ctx context.Context, tokenAddress, owner, spender string) (*big.Int, error) {
+	token, err := abi.NewAsset(common.HexToAddress(tokenAddress), c.eth)
+	if err != nil {
+		return nil, err
+	}
+	opt := &bind.CallOpts{Context: ctx}
+	return token.Allowance(opt, common.HexToAddress(owner), common.HexToAddress(spender))
+}
+
+func (c *RPCClient) FetchSafeNonce(ctx context.Context, address string, height int64) (int64, error) {
+	abi, err := c.safeContract(address)
+	if err != nil {
+		return 0, err
+	}
+
+	opt := &bind.CallOpts{Context: ctx}
+	if height > 0 {
+		opt.BlockNumber = big.NewInt(height)
+	}
+	nonce, err := abi.Nonce(opt)
+	if err != nil {
+		return 0, err
+	}
+	return nonce.Int64(), nil
+}
+
+// Deprecated: use RPCClient.GetSafeAccountGuard
+func GetSafeAccountGuard(rpc, address string) (string, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return "", err
+	}
+	return c.GetSafeAccountGuard(context.Background(), address)
+}
+
+// Deprecated: use RPCClient.GetSafeLastTxTime
+func GetSafeLastTxTime(rpc, address string) (time.Time, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.GetSafeLastTxTime(context.Background(), address)
+}
+
+// Deprecated: use RPCClient.GetOwners
+func GetOwners(rpc, address string) ([]common.Address, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetOwners(context.Background(), address)
+}
+
+// Deprecated: use RPCClient.GetThreshold
+func GetThreshold(rpc, address string) (int64, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return 0, err
+	}
+	return c.GetThreshold(context.Background(), address)
+}
+
+// Deprecated: use RPCClient.GetERC20Allowance
+func GetERC20Allowance(rpc, tokenAddress, owner, spender string) (*big.Int, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetERC20Allowance(context.Background(), tokenAddress, owner, spender)
+}
+
+// Deprecated: use RPCClient.FetchSafeNonce
+func FetchSafeNonce(ctx context.Context, rpc, address string, height int64) (int64, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return 0, err
+	}
+	return c.FetchSafeNonce(ctx, address, height)
+}
+
 func VerifyHolderKey(public string) error {
 	_, err := ParseEthereumCompressedPublicKey(public)
 	return err
@@ -128,21 +220,3 @@ func ParseEthereumUncompressedPublicKey(public string) (*common.Address, error)
 	addr := crypto.PubkeyToAddress(*publicKey)
 	return &addr, nil
 }
-
-func FetchSafeNonce(ctx context.Context, rpc, address string, height int64) (int64, error) {
-	conn, abi, err := safeInit(rpc, address)
-	if err != nil {
-		return 0, err
-	}
-	defer conn.Close()
-
-	opt := &bind.CallOpts{}
-	if height > 0 {
-		opt.BlockNumber = big.NewInt(height)
-	}
-	nonce, err := abi.Nonce(opt)
-	if err != nil {
-		return 0, err
-	}
-	return nonce.Int64(), nil
-}
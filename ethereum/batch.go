@@ -0,0 +1,181 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MixinNetwork/go-safe-sdk/common"
+)
+
+const (
+	rpcBatchSize    = 50
+	rpcBatchWorkers = 4
+)
+
+type rpcBatchRequest struct {
+	method string
+	params []any
+	result json.RawMessage
+	err    error
+}
+
+// GetBlocksWithTransactions fetches the blocks in concurrent batches, and
+// reports the error of each hash at the same index
+func (c *RPCClient) GetBlocksWithTransactions(ctx context.Context, hashes []string) ([]*RPCBlockWithTransactions, []error) {
+	params := make([][]any, len(hashes))
+	for i, h := range hashes {
+		params[i] = []any{h, true}
+	}
+	blocks, errs := callRPCBatches[RPCBlockWithTransactions](ctx, c, "eth_getBlockByHash", params)
+	for i, b := range blocks {
+		if errs[i] != nil {
+			continue
+		}
+		height, err := ethereumNumberToUint64(b.Number)
+		if err != nil {
+			blocks[i], errs[i] = nil, err
+			continue
+		}
+		b.Height = height
+		for _, tx := range b.Tx {
+			tx.BlockHash = hashes[i]
+		}
+	}
+	return blocks, errs
+}
+
+// GetTransactionsByHash is the batched GetTransactionByHash
+func (c *RPCClient) GetTransactionsByHash(ctx context.Context, hashes []string) ([]*RPCTransaction, []error) {
+	params := make([][]any, len(hashes))
+	for i, h := range hashes {
+		params[i] = []any{h}
+	}
+	txs, errs := callRPCBatches[RPCTransaction](ctx, c, "eth_getTransactionByHash", params)
+	for i, tx := range txs {
+		if errs[i] != nil {
+			continue
+		}
+		height, err := ethereumNumberToUint64(tx.BlockNumber)
+		if err != nil {
+			txs[i], errs[i] = nil, err
+			continue
+		}
+		tx.BlockHeight = height
+	}
+	return txs, errs
+}
+
+// DebugTraceTransactionsByHash is the batched DebugTraceTransactionByHash
+func (c *RPCClient) DebugTraceTransactionsByHash(ctx context.Context, hashes []string) ([]*RPCTransactionCallTrace, []error) {
+	params := make([][]any, len(hashes))
+	for i, h := range hashes {
+		if !strings.HasPrefix(h, "0x") {
+			h = "0x" + h
+		}
+		params[i] = []any{h, map[string]any{"tracer": "callTracer"}}
+	}
+	return callRPCBatches[RPCTransactionCallTrace](ctx, c, "debug_traceTransaction", params)
+}
+
+// DebugTraceBlocksByHash is the batched DebugTraceBlockByHash
+func (c *RPCClient) DebugTraceBlocksByHash(ctx context.Context, hashes []string) ([][]*RPCBlockCallTrace, []error) {
+	params := make([][]any, len(hashes))
+	for i, h := range hashes {
+		params[i] = []any{h, map[string]any{"tracer": "callTracer"}}
+	}
+	traces, errs := callRPCBatches[[]*RPCBlockCallTrace](ctx, c, "debug_traceBlockByHash", params)
+	results := make([][]*RPCBlockCallTrace, len(traces))
+	for i, t := range traces {
+		if t != nil {
+			results[i] = *t
+		}
+	}
+	return results, errs
+}
+
+// Deprecated: use RPCClient.GetBlocksWithTransactions
+func RPCGetBlocksWithTransactions(rpc string, hashes []string) ([]*RPCBlockWithTransactions, []error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return make([]*RPCBlockWithTransactions, len(hashes)), repeatError(err, len(hashes))
+	}
+	return c.GetBlocksWithTransactions(context.Background(), hashes)
+}
+
+// Deprecated: use RPCClient.GetTransactionsByHash
+func RPCGetTransactionsByHash(rpc string, hashes []string) ([]*RPCTransaction, []error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return make([]*RPCTransaction, len(hashes)), repeatError(err, len(hashes))
+	}
+	return c.GetTransactionsByHash(context.Background(), hashes)
+}
+
+// Deprecated: use RPCClient.DebugTraceTransactionsByHash
+func RPCDebugTraceTransactionsByHash(rpc string, hashes []string) ([]*RPCTransactionCallTrace, []error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return make([]*RPCTransactionCallTrace, len(hashes)), repeatError(err, len(hashes))
+	}
+	return c.DebugTraceTransactionsByHash(context.Background(), hashes)
+}
+
+// Deprecated: use RPCClient.DebugTraceBlocksByHash
+func RPCDebugTraceBlocksByHash(rpc string, hashes []string) ([][]*RPCBlockCallTrace, []error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return make([][]*RPCBlockCallTrace, len(hashes)), repeatError(err, len(hashes))
+	}
+	return c.DebugTraceBlocksByHash(context.Background(), hashes)
+}
+
+// callRPCBatches sends the same method with each params in concurrent
+// batches, a null result is reported as an error
+func callRPCBatches[T any](ctx context.Context, c *RPCClient, method string, params [][]any) ([]*T, []error) {
+	results := make([]*T, len(params))
+	errs := make([]error, len(params))
+	err := common.ForEachBatch(len(params), rpcBatchSize, rpcBatchWorkers, func(start, end int) {
+		reqs := make([]*rpcBatchRequest, end-start)
+		for i := range reqs {
+			reqs[i] = &rpcBatchRequest{method: method, params: params[start+i]}
+		}
+		for {
+			err := c.batch(ctx, reqs)
+			if err != nil && !c.failover && isRetryableRPCError(err) && ctx.Err() == nil {
+				time.Sleep(7 * time.Second)
+				continue
+			}
+			for i, r := range reqs {
+				var result *T
+				switch {
+				case err != nil:
+					errs[start+i] = err
+				case r.err != nil:
+					errs[start+i] = r.err
+				default:
+					errs[start+i] = json.Unmarshal(r.result, &result)
+				}
+				if errs[start+i] == nil && result == nil {
+					errs[start+i] = c.buildRPCError(method, r.params, fmt.Errorf("not found"))
+				}
+				results[start+i] = result
+			}
+			return
+		}
+	})
+	if err != nil {
+		return results, repeatError(err, len(params))
+	}
+	return results, errs
+}
+
+func repeatError(err error, n int) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
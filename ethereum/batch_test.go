@@ -0,0 +1,65 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthRPCBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	var batches atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batches.Add(1)
+		var reqs []map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqs)
+		assert.Nil(err)
+		var results []map[string]any
+		for _, req := range reqs {
+			res := map[string]any{"jsonrpc": "2.0", "id": req["id"]}
+			hash := req["params"].([]any)[0].(string)
+			switch {
+			case req["method"] == "debug_traceBlockByHash":
+				res["result"] = []any{map[string]any{"result": map[string]any{"from": "0x1", "to": "0x2", "value": "0x10"}}}
+			case hash == "0x07":
+				res["result"] = nil
+			case hash == "0x08":
+				res["error"] = map[string]any{"code": -32000, "message": "header not found"}
+			default:
+				res["result"] = map[string]any{
+					"hash":         hash,
+					"number":       "0x10",
+					"transactions": []any{map[string]any{"hash": "0xaa", "value": "0x1"}},
+				}
+			}
+			results = append(results, res)
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	var hashes []string
+	for i := range 120 {
+		hashes = append(hashes, fmt.Sprintf("0x%02x", i))
+	}
+	blocks, errs := RPCGetBlocksWithTransactions(server.URL, hashes)
+	assert.Len(blocks, 120)
+	assert.Equal(int64(3), batches.Load())
+	assert.NotNil(errs[7])
+	assert.NotNil(errs[8])
+	assert.Nil(blocks[8])
+	assert.Nil(errs[9])
+	assert.Equal(uint64(16), blocks[9].Height)
+	assert.Equal("0x09", blocks[9].Tx[0].BlockHash)
+
+	traces, errs := RPCDebugTraceBlocksByHash(server.URL, hashes[:2])
+	assert.Nil(errs[0])
+	assert.Nil(errs[1])
+	assert.Equal("0x10", traces[1][0].Result.Value)
+}
@@ -0,0 +1,110 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	ga "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	OperationTypeCall         = operationTypeCall
+	OperationTypeDelegateCall = operationTypeDelegateCall
+)
+
+// ContractCall is an arbitrary call made by the safe, the Data could be
+// packed by PackContractCall or provided as raw calldata
+type ContractCall struct {
+	Destination string
+	Value       *big.Int
+	Data        []byte
+	Operation   uint8
+}
+
+var (
+	delegateCallLock    sync.RWMutex
+	delegateCallTargets = make(map[int64]map[common.Address]bool)
+)
+
+// AllowDelegateCallTarget permits delegate calls to the target on the EVM
+// chain, the MultiSend of the safe deployment is always allowed
+func AllowDelegateCallTarget(chainID int64, target string) error {
+	norm := NormalizeAddress(target)
+	if norm == "" {
+		return fmt.Errorf("invalid delegate call target %s", target)
+	}
+	delegateCallLock.Lock()
+	defer delegateCallLock.Unlock()
+
+	if delegateCallTargets[chainID] == nil {
+		delegateCallTargets[chainID] = make(map[common.Address]bool)
+	}
+	delegateCallTargets[chainID][common.HexToAddress(norm)] = true
+	return nil
+}
+
+func IsDelegateCallAllowed(chainID int64, target common.Address) bool {
+	if target == common.HexToAddress(GetSafeDeployment(chainID).MultiSend) {
+		return true
+	}
+	delegateCallLock.RLock()
+	defer delegateCallLock.RUnlock()
+	return delegateCallTargets[chainID][target]
+}
+
+func PackContractCall(abiJSON, method string, args ...any) ([]byte, error) {
+	parsed, err := ga.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract abi %v", err)
+	}
+	return parsed.Pack(method, args...)
+}
+
+func CreateContractCallTransaction(ctx context.Context, chainID int64, id, safeAddress string, call *ContractCall, nonce *big.Int) (*SafeTransaction, error) {
+	if nonce == nil {
+		return nil, fmt.Errorf("Invalid ethereum transaction nonce")
+	}
+	norm := NormalizeAddress(call.Destination)
+	if norm == "" {
+		return nil, fmt.Errorf("invalid contract call destination %s", call.Destination)
+	}
+	destination := common.HexToAddress(norm)
+	value := call.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid contract call value %s", value)
+	}
+	switch call.Operation {
+	case operationTypeCall:
+	case operationTypeDelegateCall:
+		if !IsDelegateCallAllowed(chainID, destination) {
+			return nil, fmt.Errorf("delegate call to %s is not allowed", norm)
+		}
+	default:
+		return nil, fmt.Errorf("invalid contract call operation %d", call.Operation)
+	}
+
+	tx := &SafeTransaction{
+		ChainID:        chainID,
+		SafeAddress:    safeAddress,
+		Destination:    destination,
+		Value:          value,
+		Data:           call.Data,
+		Operation:      call.Operation,
+		SafeTxGas:      big.NewInt(0),
+		BaseGas:        big.NewInt(0),
+		GasPrice:       big.NewInt(0),
+		GasToken:       common.HexToAddress(EthereumEmptyAddress),
+		RefundReceiver: common.HexToAddress(EthereumEmptyAddress),
+		Nonce:          nonce,
+	}
+	tx.Message = tx.GetTransactionHash()
+	tx.TxHash = tx.Hash(id)
+	return tx, nil
+}
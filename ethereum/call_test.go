@@ -0,0 +1,53 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthContractCallTx(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	router := testReceiverAddress
+	deposit := `[{"inputs":[{"name":"receiver","type":"address"},{"name":"amount","type":"uint256"}],"name":"deposit","outputs":[],"stateMutability":"payable","type":"function"}]`
+	data, err := PackContractCall(deposit, "deposit", common.HexToAddress(testSafeAddress), big.NewInt(100))
+	assert.Nil(err)
+	assert.Len(data, 68)
+	_, err = PackContractCall(deposit, "withdraw")
+	assert.NotNil(err)
+
+	call := &ContractCall{Destination: router, Value: big.NewInt(100), Data: data}
+	tx, err := CreateContractCallTransaction(ctx, 137, testTransactionId, testSafeAddress, call, big.NewInt(3))
+	assert.Nil(err)
+	assert.Equal(uint8(OperationTypeCall), tx.Operation)
+	assert.Equal(data, tx.Data)
+	st, err := UnmarshalSafeTransaction(tx.Marshal())
+	assert.Nil(err)
+	assert.Equal(tx.GetTransactionHash(), st.Message)
+	actions, err := tx.DecodeActions()
+	assert.Nil(err)
+	assert.Equal(SafeActionUnknown, actions[0].Method)
+
+	call.Operation = OperationTypeDelegateCall
+	_, err = CreateContractCallTransaction(ctx, 137, testTransactionId, testSafeAddress, call, big.NewInt(3))
+	assert.NotNil(err)
+	err = AllowDelegateCallTarget(137, router)
+	assert.Nil(err)
+	tx, err = CreateContractCallTransaction(ctx, 137, testTransactionId, testSafeAddress, call, big.NewInt(3))
+	assert.Nil(err)
+	assert.Equal(uint8(OperationTypeDelegateCall), tx.Operation)
+	_, err = CreateContractCallTransaction(ctx, 1, testTransactionId, testSafeAddress, call, big.NewInt(3))
+	assert.NotNil(err)
+
+	call.Destination, call.Data = EthereumMultiSendAddress, []byte{1, 2, 3, 4}
+	_, err = CreateContractCallTransaction(ctx, 1, testTransactionId, testSafeAddress, call, big.NewInt(3))
+	assert.Nil(err)
+	call.Operation = 2
+	_, err = CreateContractCallTransaction(ctx, 1, testTransactionId, testSafeAddress, call, big.NewInt(3))
+	assert.NotNil(err)
+}
@@ -0,0 +1,262 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type RPCConfig struct {
+	Endpoint string
+	// sent with every request, e.g. the API key header of the provider
+	Headers map[string]string
+	// requests per second with the burst, zero for unlimited
+	RateLimit float64
+	RateBurst int
+	Timeout   time.Duration
+}
+
+// RPCClient shares the transport between the JSON-RPC calls and the ethclient
+// for the contract bindings, so all requests reuse connections and are rate
+// limited together
+type RPCClient struct {
+	config RPCConfig
+	client *http.Client
+	eth    *ethclient.Client
+	// the MultiRPCClient fails over to the next endpoint instead of retrying
+	failover bool
+}
+
+// All clients share the transport to reuse connections to the same provider
+var rpcTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+func NewRPCClient(config RPCConfig) (*RPCClient, error) {
+	if config.Timeout == 0 {
+		config.Timeout = 20 * time.Second
+	}
+	transport := &rpcRoundTripper{headers: config.Headers}
+	if config.RateLimit > 0 {
+		transport.limiter = newTokenBucket(config.RateLimit, max(config.RateBurst, 1))
+	}
+	client := &http.Client{Transport: transport, Timeout: config.Timeout}
+	conn, err := rpc.DialOptions(context.Background(), config.Endpoint, rpc.WithHTTPClient(client))
+	if err != nil {
+		return nil, err
+	}
+	return &RPCClient{
+		config: config,
+		client: client,
+		eth:    ethclient.NewClient(conn),
+	}, nil
+}
+
+// Eth returns the ethclient of the same transport, e.g. as the RelayerBackend
+func (c *RPCClient) Eth() *ethclient.Client {
+	return c.eth
+}
+
+func (c *RPCClient) Close() {
+	c.eth.Close()
+}
+
+var defaultRPCClients sync.Map
+
+// defaultRPCClient is cached for the endpoint of the deprecated functions
+func defaultRPCClient(endpoint string) (*RPCClient, error) {
+	if c, found := defaultRPCClients.Load(endpoint); found {
+		return c.(*RPCClient), nil
+	}
+	c, err := NewRPCClient(RPCConfig{Endpoint: endpoint})
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := defaultRPCClients.LoadOrStore(endpoint, c)
+	return actual.(*RPCClient), nil
+}
+
+func (c *RPCClient) safeContract(address string) (*abi.GnosisSafe, error) {
+	return abi.NewGnosisSafe(common.HexToAddress(address), c.eth)
+}
+
+func (c *RPCClient) guardContract(address string) (*abi.MixinSafeGuard, error) {
+	return abi.NewMixinSafeGuard(common.HexToAddress(address), c.eth)
+}
+
+// callUntilSufficient retries on network errors until the context is done,
+// except in a MultiRPCClient which tries the other endpoints
+func (c *RPCClient) callUntilSufficient(ctx context.Context, method string, params []any) ([]byte, error) {
+	for {
+		res, err := c.call(ctx, method, params)
+		if err == nil || c.failover || !isRetryableRPCError(err) {
+			return res, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(7 * time.Second):
+		}
+	}
+}
+
+func (c *RPCClient) call(ctx context.Context, method string, params []any) ([]byte, error) {
+	body, err := json.Marshal(map[string]any{
+		"method":  method,
+		"params":  params,
+		"id":      time.Now().UnixNano(),
+		"jsonrpc": "2.0",
+	})
+	if err != nil {
+		panic(err)
+	}
+	body, err = c.post(ctx, body)
+	if err != nil {
+		return nil, c.buildRPCError(method, params, err)
+	}
+	var result struct {
+		Data  any `json:"result"`
+		Error any `json:"error"`
+	}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", c.buildRPCError(method, params, err), string(body))
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("%w (%s)", c.buildRPCError(method, params, fmt.Errorf("%v", result.Error)), string(body))
+	}
+	return json.Marshal(result.Data)
+}
+
+func (c *RPCClient) batch(ctx context.Context, reqs []*rpcBatchRequest) error {
+	method := fmt.Sprintf("batch(%s, %d)", reqs[0].method, len(reqs))
+	msgs := make([]map[string]any, len(reqs))
+	for i, r := range reqs {
+		msgs[i] = map[string]any{
+			"method":  r.method,
+			"params":  r.params,
+			"id":      i,
+			"jsonrpc": "2.0",
+		}
+	}
+	body, err := json.Marshal(msgs)
+	if err != nil {
+		panic(err)
+	}
+	body, err = c.post(ctx, body)
+	if err != nil {
+		return c.buildRPCError(method, nil, err)
+	}
+	var results []struct {
+		Id     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  any             `json:"error"`
+	}
+	err = json.Unmarshal(body, &results)
+	if err != nil {
+		return fmt.Errorf("%w (%s)", c.buildRPCError(method, nil, err), string(body))
+	}
+	for _, r := range reqs {
+		r.err = c.buildRPCError(r.method, r.params, fmt.Errorf("no response"))
+	}
+	for _, res := range results {
+		if res.Id < 0 || res.Id >= len(reqs) {
+			continue
+		}
+		r := reqs[res.Id]
+		if res.Error != nil {
+			r.err = c.buildRPCError(r.method, r.params, fmt.Errorf("%v", res.Error))
+			continue
+		}
+		r.result, r.err = res.Result, nil
+	}
+	return nil
+}
+
+func (c *RPCClient) post(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (c *RPCClient) buildRPCError(method string, params []any, err error) error {
+	return fmt.Errorf("callEthereumRPC(%s, %s, %v) => %w", c.config.Endpoint, method, params, err)
+}
+
+// rpcRoundTripper sets the headers and waits for the rate limiter before
+// each request, including those from the ethclient
+type rpcRoundTripper struct {
+	headers map[string]string
+	limiter *tokenBucket
+}
+
+func (t *rpcRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter != nil {
+		err := t.limiter.Wait(req.Context())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(t.headers) > 0 {
+		req = req.Clone(req.Context())
+		for k, v := range t.headers {
+			req.Header.Set(k, v)
+		}
+	}
+	return rpcTransport.RoundTrip(req)
+}
+
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		b.mutex.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens = b.tokens - 1
+			b.mutex.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ethereum
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/md5"
 	"encoding/hex"
@@ -10,19 +11,26 @@ import (
 	"strings"
 	"time"
 
+	sc "github.com/MixinNetwork/go-safe-sdk/common"
 	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
 	ga "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/gofrs/uuid/v5"
 	"github.com/shopspring/decimal"
 )
 
 const (
-	ChainEthereum = 2
-	ChainMVM      = 4
-	ChainPolygon  = 6
+	ChainEthereum = sc.ChainEthereum
+	ChainMVM      = sc.ChainMVM
+	ChainPolygon  = sc.ChainPolygon
+
+	ChainArbitrum  = sc.ChainArbitrum
+	ChainOptimism  = sc.ChainOptimism
+	ChainBase      = sc.ChainBase
+	ChainBNB       = sc.ChainBNB
+	ChainAvalanche = sc.ChainAvalanche
 
 	TransactionConfirmations = 1
 
@@ -35,9 +43,10 @@ const (
 	operationTypeCall         = 0
 	operationTypeDelegateCall = 1
 
-	TypeETHTx       = 1
-	TypeERC20Tx     = 2
-	TypeMultiSendTx = 3
+	TypeETHTx          = 1
+	TypeERC20Tx        = 2
+	TypeMultiSendTx    = 3
+	TypeERC20ApproveTx = 4
 
 	EthereumEmptyAddress                        = "0x0000000000000000000000000000000000000000"
 	EthereumSafeProxyFactoryAddress             = "0x4e1DCf7AD4e460CfD30791CCC4F9c8a4f820ec67"
@@ -145,53 +154,43 @@ func UnitAmount(amount *big.Int, decimals int32) string {
 }
 
 func GetEvmChainID(chain int64) int64 {
-	switch chain {
-	case ChainEthereum:
-		return 1
-	case ChainPolygon:
-		return 137
-	case ChainMVM:
-		return 73927
-	default:
-		panic(chain)
-	}
+	return readEthereumChain(chain).EvmChainId
 }
 
 func GetMixinChainID(chain int64) string {
-	switch chain {
-	case ChainEthereum:
-		return "43d61dcd-e413-450d-80b8-101d5e903357"
-	case ChainPolygon:
-		return "b7938396-3f94-4e0a-9179-d3440718156f"
-	case ChainMVM:
-		return "a0ffd769-5850-4b48-9651-d2ae44a3e64d"
-	default:
+	return readEthereumChain(chain).AssetId
+}
+
+func GetGasSymbol(chain int64) string {
+	return readEthereumChain(chain).Symbol
+}
+
+func readEthereumChain(chain int64) *sc.ChainInfo {
+	c, err := sc.ReadChain(byte(chain))
+	if err != nil || c.Family != sc.FamilyEthereum {
 		panic(chain)
 	}
+	return c
 }
 
-func FetchAsset(chain byte, rpc, address string) (*Asset, error) {
+func (c *RPCClient) FetchAsset(ctx context.Context, chain byte, address string) (*Asset, error) {
 	addr := common.HexToAddress(address)
 	assetId := GenerateAssetId(chain, address)
 
-	conn, err := ethclient.Dial(rpc)
+	token, err := abi.NewAsset(addr, c.eth)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
-	token, err := abi.NewAsset(addr, conn)
+	opt := &bind.CallOpts{Context: ctx}
+	name, err := token.Name(opt)
 	if err != nil {
 		return nil, err
 	}
-	name, err := token.Name(nil)
+	symbol, err := token.Symbol(opt)
 	if err != nil {
 		return nil, err
 	}
-	symbol, err := token.Symbol(nil)
-	if err != nil {
-		return nil, err
-	}
-	decimals, err := token.Decimals(nil)
+	decimals, err := token.Decimals(opt)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +205,15 @@ func FetchAsset(chain byte, rpc, address string) (*Asset, error) {
 	}, nil
 }
 
+// Deprecated: use RPCClient.FetchAsset
+func FetchAsset(chain byte, rpc, address string) (*Asset, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	return c.FetchAsset(context.Background(), chain, address)
+}
+
 func NormalizeAddress(addr string) string {
 	norm := common.HexToAddress(addr).Hex()
 	if norm == EthereumEmptyAddress || !strings.EqualFold(norm, addr) {
@@ -339,34 +347,6 @@ func packDomainSeparatorArguments(chainID int64, safeAddress string) []byte {
 	return args
 }
 
-func guardInit(rpc string, guard string) (*ethclient.Client, *abi.MixinSafeGuard, error) {
-	conn, err := ethclient.Dial(rpc)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	abi, err := abi.NewMixinSafeGuard(common.HexToAddress(guard), conn)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return conn, abi, nil
-}
-
-func safeInit(rpc, address string) (*ethclient.Client, *abi.GnosisSafe, error) {
-	conn, err := ethclient.Dial(rpc)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	abi, err := abi.NewGnosisSafe(common.HexToAddress(address), conn)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return conn, abi, nil
-}
-
 func toBytes32(b []byte) [32]byte {
 	var b32 [32]byte
 	copy(b32[:], b[:32])
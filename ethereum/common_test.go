@@ -0,0 +1,41 @@
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSafeAddress     = "0x3e7A4c7e2fa3dd1c3D8d4E3B8b4c8C0E5B7A3A11"
+	testReceiverAddress = "0xA03A8590BB3A2cA5c747c8b99C63DA399424a055"
+	testTransactionId   = "b0d6e8a1-0ba4-4e1a-a90c-3e1c3c4f5c2d"
+)
+
+// testLegacyTransaction is a polygon multisend of the legacy encoding, with
+// a native and an erc20 transfer and 3 signature slots
+const testLegacyTransaction = "00000000000000890000000000000001004066336238653462336561303462303137636630383961323039363962323661333264353232333836636331343064663734343435383535376133373065636431002a307834663539373461303536303239454641376534423762353161374262636238464563364538393730001438869bf66a61cf6bdb996a6ae40d5853fd43b526000001448d80ff0a000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000ee00a03a8590bb3a2ca5c747c8b99c63da399424a05500000000000000000000000000000000000000000000000000005af3107a4000000000000000000000000000000000000000000000000000000000000000000000c2132d05d31c914a87c6611c10748aeb04b58e8f00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000044a9059cbb000000000000000000000000a03a8590bb3a2ca5c747c8b99c63da399424a05500000000000000000000000000000000000000000000000000000000000000c80000000000000000000000000000000000000001010020288302032801fdd390a9714e4c2b8421658c9d4723bfcc8b4431b0aae098452100022c2c"
+
+// testOwners generates the keys and addresses of n safe owners
+func testOwners(n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	var keys []*ecdsa.PrivateKey
+	var owners []common.Address
+	for range n {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		owners = append(owners, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	return keys, owners
+}
+
+// testTransfer creates an unsigned native transfer from the test safe
+func testTransfer(t *testing.T, chainID int64) *SafeTransaction {
+	tx, err := CreateTransaction(context.Background(), TypeETHTx, chainID, testTransactionId, testSafeAddress, testReceiverAddress, "", "1000", big.NewInt(1))
+	assert.Nil(t, err)
+	return tx
+}
@@ -0,0 +1,158 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	ga "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	SafeActionNativeTransfer = "nativeTransfer"
+	SafeActionUnknown        = "unknown"
+)
+
+// SafeAction is a single call made by the safe transaction, Method is the
+// ABI method name, or SafeActionNativeTransfer for the plain value transfer,
+// or SafeActionUnknown with the raw Data if the selector is not recognized
+type SafeAction struct {
+	Operation uint8
+	To        common.Address
+	Value     *big.Int
+	Method    string
+	Selector  string
+	Args      map[string]any
+	Data      []byte
+}
+
+// the methods decoded from the bundled ABIs, all others are reported raw
+var safeActionMethods = map[string][]string{
+	abi.GnosisSafeMetaData.ABI: {
+		"setGuard", "addOwnerWithThreshold", "removeOwner", "swapOwner",
+		"changeThreshold", "enableModule", "disableModule", "setFallbackHandler",
+		"approveHash",
+	},
+	abi.MixinSafeGuardMetaData.ABI: {"guardSafe"},
+	abi.AssetMetaData.ABI:          {"transfer", "approve", "transferFrom"},
+	erc721ABI:                      {"safeTransferFrom"},
+	erc1155ABI:                     {"safeTransferFrom", "safeBatchTransferFrom"},
+}
+
+// DecodeActions turns the safe transaction into the calls it will make, the
+// MultiSend delegate call is expanded into all of its transactions
+func (tx *SafeTransaction) DecodeActions() ([]*SafeAction, error) {
+	value := tx.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	if tx.Operation == operationTypeDelegateCall && len(tx.Data) >= 4 {
+		multiSend, err := ga.JSON(strings.NewReader(abi.MultiSendMetaData.ABI))
+		if err != nil {
+			panic(err)
+		}
+		method, err := multiSend.MethodById(tx.Data[:4])
+		if err == nil && method.Name == "multiSend" {
+			return decodeMultiSendActions(method, tx.Data[4:])
+		}
+	}
+	return []*SafeAction{decodeSafeAction(tx.Operation, tx.Destination, value, tx.Data)}, nil
+}
+
+func (a *SafeAction) String() string {
+	var args []string
+	for k, v := range a.Args {
+		args = append(args, fmt.Sprintf("%s=%v", k, formatActionArgument(v)))
+	}
+	sort.Strings(args)
+	switch a.Method {
+	case SafeActionNativeTransfer:
+		return fmt.Sprintf("%s(to=%s, value=%s)", a.Method, a.To.Hex(), a.Value)
+	case SafeActionUnknown:
+		return fmt.Sprintf("%s(to=%s, value=%s, data=%x)", a.Method, a.To.Hex(), a.Value, a.Data)
+	}
+	return fmt.Sprintf("%s.%s(%s)", a.To.Hex(), a.Method, strings.Join(args, ", "))
+}
+
+func decodeMultiSendActions(method *ga.Method, input []byte) ([]*SafeAction, error) {
+	args, err := method.Inputs.Unpack(input)
+	if err != nil || len(args) != 1 {
+		return nil, fmt.Errorf("invalid multiSend data %x", input)
+	}
+	data, ok := args[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid multiSend data %x", input)
+	}
+
+	var actions []*SafeAction
+	for offset := 0; offset < len(data); {
+		if len(data)-offset < 85 {
+			return nil, fmt.Errorf("invalid multiSend transaction at %d", offset)
+		}
+		op := data[offset]
+		to := common.BytesToAddress(data[offset+1 : offset+21])
+		value := new(big.Int).SetBytes(data[offset+21 : offset+53])
+		size := new(big.Int).SetBytes(data[offset+53 : offset+85])
+		offset += 85
+		if !size.IsInt64() || size.Int64() > int64(len(data)-offset) {
+			return nil, fmt.Errorf("invalid multiSend transaction data size %s", size)
+		}
+		call := data[offset : offset+int(size.Int64())]
+		offset += int(size.Int64())
+		actions = append(actions, decodeSafeAction(op, to, value, call))
+	}
+	return actions, nil
+}
+
+func decodeSafeAction(op uint8, to common.Address, value *big.Int, data []byte) *SafeAction {
+	action := &SafeAction{
+		Operation: op,
+		To:        to,
+		Value:     value,
+		Method:    SafeActionUnknown,
+		Data:      data,
+	}
+	if len(data) == 0 {
+		action.Method = SafeActionNativeTransfer
+		return action
+	}
+	if len(data) < 4 {
+		return action
+	}
+	action.Selector = hex.EncodeToString(data[:4])
+	for def, names := range safeActionMethods {
+		parsed, err := ga.JSON(strings.NewReader(def))
+		if err != nil {
+			panic(err)
+		}
+		method, err := parsed.MethodById(data[:4])
+		if err != nil || !slices.Contains(names, method.Name) {
+			continue
+		}
+		args := make(map[string]any)
+		err = method.Inputs.UnpackIntoMap(args, data[4:])
+		if err != nil {
+			return action
+		}
+		action.Method, action.Args = method.Name, args
+		return action
+	}
+	return action
+}
+
+func formatActionArgument(v any) any {
+	switch v := v.(type) {
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hex.EncodeToString(v)
+	case [32]byte:
+		return hex.EncodeToString(v[:])
+	}
+	return v
+}
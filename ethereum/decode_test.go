@@ -0,0 +1,55 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEthTxActions(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	st, err := UnmarshalSafeTransaction(common.FromHex(testLegacyTransaction))
+	assert.Nil(err)
+	actions, err := st.DecodeActions()
+	assert.Nil(err)
+	assert.Len(actions, 2)
+	assert.Equal(SafeActionNativeTransfer, actions[0].Method)
+	assert.Equal(big.NewInt(100000000000000), actions[0].Value)
+	assert.Equal("transfer", actions[1].Method)
+	assert.Equal("a9059cbb", actions[1].Selector)
+	assert.Equal(big.NewInt(200), actions[1].Args["value"])
+	assert.Contains(actions[1].String(), "0xc2132D05D31c914a87C6611C10748AEb04B58e8F.transfer(")
+
+	st, err = CreateEnableGuardTransaction(ctx, 137, testTransactionId, testSafeAddress, testReceiverAddress, big.NewInt(3600))
+	assert.Nil(err)
+	actions, err = st.DecodeActions()
+	assert.Nil(err)
+	assert.Len(actions, 2)
+	assert.Equal("setGuard", actions[0].Method)
+	assert.Equal(common.HexToAddress(testSafeAddress), actions[0].To)
+	assert.Equal(common.HexToAddress(EthereumSafeGuardAddress), actions[0].Args["guard"])
+	assert.Equal("guardSafe", actions[1].Method)
+	assert.Equal(common.HexToAddress(testReceiverAddress), actions[1].Args["observerAddress"])
+	assert.Equal(big.NewInt(3600), actions[1].Args["timelock"])
+
+	for _, data := range [][]byte{
+		{0x01, 0x02},
+		{0xde, 0xad, 0xbe, 0xef, 0x01},
+		common.FromHex("0xa9059cbb0000"),
+	} {
+		st.Operation, st.Data = 0, data
+		actions, err = st.DecodeActions()
+		assert.Nil(err)
+		assert.Len(actions, 1)
+		assert.Equal(SafeActionUnknown, actions[0].Method)
+		assert.Equal(data, actions[0].Data)
+	}
+	st.Operation, st.Data = 1, common.FromHex("0x8d80ff0a00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002aabb000000000000000000000000000000000000000000000000000000000000")
+	_, err = st.DecodeActions()
+	assert.NotNil(err)
+}
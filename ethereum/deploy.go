@@ -0,0 +1,133 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	sc "github.com/MixinNetwork/go-safe-sdk/common"
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	ga "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// GetSafeSetupData returns the initializer of the safe proxy, which calls
+// setup with the owners, threshold and the fallback handler of the chain.
+// The owners are sorted by their checksum hex in descending order, the same
+// as the keeper, so the safe address doesn't depend on the order given.
+func GetSafeSetupData(owners []string, threshold int64, chain byte) ([]byte, error) {
+	if threshold < 1 || threshold > int64(len(owners)) {
+		return nil, fmt.Errorf("invalid threshold %d for owners %d", threshold, len(owners))
+	}
+	var sorted []string
+	for _, o := range owners {
+		norm := NormalizeAddress(o)
+		if norm == "" {
+			return nil, fmt.Errorf("invalid safe owner %s", o)
+		}
+		if slices.Contains(sorted, norm) {
+			return nil, fmt.Errorf("duplicated safe owner %s", o)
+		}
+		sorted = append(sorted, norm)
+	}
+	slices.SortFunc(sorted, func(a, b string) int {
+		return strings.Compare(b, a)
+	})
+	var addrs []common.Address
+	for _, o := range sorted {
+		addrs = append(addrs, common.HexToAddress(o))
+	}
+	d, err := readSafeDeployment(chain)
+	if err != nil {
+		return nil, err
+	}
+	empty := common.HexToAddress(EthereumEmptyAddress)
+
+	safeAbi, err := ga.JSON(strings.NewReader(abi.GnosisSafeMetaData.ABI))
+	if err != nil {
+		panic(err)
+	}
+	return safeAbi.Pack(
+		"setup",
+		addrs,
+		big.NewInt(threshold),
+		empty,
+		[]byte{},
+		common.HexToAddress(d.FallbackHandler),
+		empty,
+		big.NewInt(0),
+		empty,
+	)
+}
+
+// GetSafeProxyCreationData returns the proxy factory address and the calldata
+// of createProxyWithNonce to deploy the safe
+func GetSafeProxyCreationData(owners []string, threshold int64, chain byte) (common.Address, []byte, error) {
+	initializer, err := GetSafeSetupData(owners, threshold, chain)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	d, err := readSafeDeployment(chain)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	factoryAbi, err := ga.JSON(strings.NewReader(abi.ProxyFactoryMetaData.ABI))
+	if err != nil {
+		panic(err)
+	}
+	data, err := factoryAbi.Pack(
+		"createProxyWithNonce",
+		common.HexToAddress(d.SafeL2),
+		initializer,
+		saltNonce(),
+	)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return common.HexToAddress(d.ProxyFactory), data, nil
+}
+
+// PredictSafeAddress computes the CREATE2 address of the safe proxy, in the
+// same way as createProxyWithNonce of the Safe 1.4.1 proxy factory
+func PredictSafeAddress(owners []string, threshold int64, chain byte) (string, error) {
+	initializer, err := GetSafeSetupData(owners, threshold, chain)
+	if err != nil {
+		return "", err
+	}
+	d, err := readSafeDeployment(chain)
+	if err != nil {
+		return "", err
+	}
+
+	salt := crypto.Keccak256(crypto.Keccak256(initializer), math.U256Bytes(saltNonce()))
+	code, err := hex.DecodeString(accountContractCode[2:])
+	if err != nil {
+		panic(err)
+	}
+	code = append(code, common.LeftPadBytes(common.HexToAddress(d.SafeL2).Bytes(), 32)...)
+	addr := crypto.CreateAddress2(common.HexToAddress(d.ProxyFactory), common.BytesToHash(salt), crypto.Keccak256(code))
+	return addr.Hex(), nil
+}
+
+func readSafeDeployment(chain byte) (*SafeDeployment, error) {
+	c, err := sc.ReadChain(chain)
+	if err != nil {
+		return nil, err
+	}
+	if c.Family != sc.FamilyEthereum {
+		return nil, fmt.Errorf("invalid ethereum chain %d", chain)
+	}
+	return GetSafeDeployment(c.EvmChainId), nil
+}
+
+func saltNonce() *big.Int {
+	nonce, ok := new(big.Int).SetString(predeterminedSaltNonce[2:], 16)
+	if !ok {
+		panic(predeterminedSaltNonce)
+	}
+	return nonce
+}
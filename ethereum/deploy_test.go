@@ -0,0 +1,69 @@
+package ethereum
+
+import (
+	"testing"
+
+	sc "github.com/MixinNetwork/go-safe-sdk/common"
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredictSafeAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	owners := []string{
+		"0x9d04735aaEB73535672200950fA77C2dFC86eB21",
+		"0x4B6C4Aa4E2A6C7DF1F4D2E4a0B1a8bC9b8A0E4f2",
+		"0xBa0F5A1C7C8d0eE3dE0bF3d4A1B2c3D4e5F6a7B8",
+	}
+	// FIXME compare with a safe deployed by the v1.4.1 proxy factory, this
+	// address is only computed by PredictSafeAddress itself
+	addr, err := PredictSafeAddress(owners, 2, ChainPolygon)
+	assert.Nil(err)
+	assert.Equal("0xbb27B6d83F769233CA4e5b3F32a2Bc9a162C4b75", addr)
+	again, err := PredictSafeAddress(owners, 2, ChainPolygon)
+	assert.Nil(err)
+	assert.Equal(addr, again)
+	other, err := PredictSafeAddress(owners, 3, ChainPolygon)
+	assert.Nil(err)
+	assert.NotEqual(addr, other)
+	other, err = PredictSafeAddress([]string{owners[1], owners[0], owners[2]}, 2, ChainPolygon)
+	assert.Nil(err)
+	assert.Equal(addr, other)
+	_, err = PredictSafeAddress(owners, 4, ChainPolygon)
+	assert.NotNil(err)
+	_, err = PredictSafeAddress([]string{owners[0], owners[0]}, 1, ChainPolygon)
+	assert.NotNil(err)
+
+	factory, data, err := GetSafeProxyCreationData(owners, 2, ChainPolygon)
+	assert.Nil(err)
+	assert.Equal(EthereumSafeProxyFactoryAddress, factory.Hex())
+	factoryAbi, err := abi.ProxyFactoryMetaData.GetAbi()
+	assert.Nil(err)
+	method, err := factoryAbi.MethodById(data[:4])
+	assert.Nil(err)
+	assert.Equal("createProxyWithNonce", method.Name)
+	args, err := method.Inputs.Unpack(data[4:])
+	assert.Nil(err)
+	assert.Equal(EthereumSafeL2Address, args[0].(common.Address).Hex())
+	initializer, err := GetSafeSetupData(owners, 2, ChainPolygon)
+	assert.Nil(err)
+	assert.Equal(initializer, args[1].([]byte))
+	safeAbi, err := abi.GnosisSafeMetaData.GetAbi()
+	assert.Nil(err)
+	setup, err := safeAbi.Methods["setup"].Inputs.Unpack(initializer[4:])
+	assert.Nil(err)
+	assert.Equal([]common.Address{
+		common.HexToAddress(owners[2]),
+		common.HexToAddress(owners[0]),
+		common.HexToAddress(owners[1]),
+	}, setup[0].([]common.Address))
+
+	for _, chain := range []byte{sc.ChainBitcoin, 0} {
+		_, err = PredictSafeAddress(owners, 2, chain)
+		assert.NotNil(err)
+		_, _, err = GetSafeProxyCreationData(owners, 2, chain)
+		assert.NotNil(err)
+	}
+}
@@ -0,0 +1,91 @@
+package ethereum
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SafeDeployment holds the contract addresses used by safe accounts on an
+// EVM chain, the canonical Safe 1.4.1 deployments share the same addresses
+// on most chains, so a chain only needs registration when it differs. The
+// safe guard is not canonical, and it must be registered for each chain.
+type SafeDeployment struct {
+	ProxyFactory    string
+	SafeL2          string
+	FallbackHandler string
+	MultiSend       string
+	SafeGuard       string
+}
+
+var (
+	deploymentsLock sync.RWMutex
+	deployments     = make(map[int64]*SafeDeployment)
+
+	defaultDeployment = SafeDeployment{
+		ProxyFactory:    EthereumSafeProxyFactoryAddress,
+		SafeL2:          EthereumSafeL2Address,
+		FallbackHandler: EthereumCompatibilityFallbackHandlerAddress,
+		MultiSend:       EthereumMultiSendAddress,
+	}
+)
+
+func init() {
+	for _, chain := range []int64{
+		ChainEthereum, ChainMVM, ChainPolygon,
+		ChainArbitrum, ChainOptimism, ChainBase, ChainBNB, ChainAvalanche,
+	} {
+		err := RegisterSafeDeployment(GetEvmChainID(chain), &SafeDeployment{
+			SafeGuard: EthereumSafeGuardAddress,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterSafeDeployment overrides the contract addresses of the EVM chain
+// id, empty addresses keep the registered ones, or fall back to the Ethereum
+// deployment except the safe guard
+func RegisterSafeDeployment(chainID int64, d *SafeDeployment) error {
+	if chainID <= 0 {
+		return fmt.Errorf("invalid evm chain id %d", chainID)
+	}
+	sd := *GetSafeDeployment(chainID)
+	for _, a := range []struct {
+		src string
+		dst *string
+	}{
+		{d.ProxyFactory, &sd.ProxyFactory},
+		{d.SafeL2, &sd.SafeL2},
+		{d.FallbackHandler, &sd.FallbackHandler},
+		{d.MultiSend, &sd.MultiSend},
+		{d.SafeGuard, &sd.SafeGuard},
+	} {
+		if a.src == "" {
+			continue
+		}
+		if !common.IsHexAddress(a.src) || common.HexToAddress(a.src).Hex() == EthereumEmptyAddress {
+			return fmt.Errorf("invalid safe deployment address %s", a.src)
+		}
+		*a.dst = common.HexToAddress(a.src).Hex()
+	}
+
+	deploymentsLock.Lock()
+	defer deploymentsLock.Unlock()
+	deployments[chainID] = &sd
+	return nil
+}
+
+func GetSafeDeployment(chainID int64) *SafeDeployment {
+	deploymentsLock.RLock()
+	defer deploymentsLock.RUnlock()
+
+	d := deployments[chainID]
+	if d == nil {
+		d = &defaultDeployment
+	}
+	sd := *d
+	return &sd
+}
@@ -0,0 +1,205 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	safeEventMaxRange = 2000
+	safeEventMinRange = 1
+)
+
+// SafeEvent is a decoded log of the safe, and Event is the typed struct of
+// the abi binding, e.g. *abi.GnosisSafeExecutionSuccess. MixinSafeGuard
+// declares no events, and the guard changes are ChangedGuard of the safe.
+type SafeEvent struct {
+	Name            string
+	Address         string
+	BlockHash       string
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+	Event           any
+}
+
+var safeEventParsers = map[string]func(f *abi.GnosisSafeFilterer, l types.Log) (any, error){
+	"AddedOwner":                 func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseAddedOwner(l) },
+	"ApproveHash":                func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseApproveHash(l) },
+	"ChangedFallbackHandler":     func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseChangedFallbackHandler(l) },
+	"ChangedGuard":               func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseChangedGuard(l) },
+	"ChangedThreshold":           func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseChangedThreshold(l) },
+	"DisabledModule":             func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseDisabledModule(l) },
+	"EnabledModule":              func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseEnabledModule(l) },
+	"ExecutionFailure":           func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseExecutionFailure(l) },
+	"ExecutionFromModuleFailure": func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseExecutionFromModuleFailure(l) },
+	"ExecutionFromModuleSuccess": func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseExecutionFromModuleSuccess(l) },
+	"ExecutionSuccess":           func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseExecutionSuccess(l) },
+	"RemovedOwner":               func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseRemovedOwner(l) },
+	"SafeModuleTransaction":      func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseSafeModuleTransaction(l) },
+	"SafeMultiSigTransaction":    func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseSafeMultiSigTransaction(l) },
+	"SafeReceived":               func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseSafeReceived(l) },
+	"SafeSetup":                  func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseSafeSetup(l) },
+	"SignMsg":                    func(f *abi.GnosisSafeFilterer, l types.Log) (any, error) { return f.ParseSignMsg(l) },
+}
+
+// DecodeSafeEvent returns nil for the log not of any safe event
+func DecodeSafeEvent(l *RPCLog) (*SafeEvent, error) {
+	if len(l.Topics) == 0 {
+		return nil, nil
+	}
+	safeAbi, err := abi.GnosisSafeMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	event, err := safeAbi.EventByID(common.HexToHash(l.Topics[0]))
+	if err != nil {
+		return nil, nil
+	}
+	parse := safeEventParsers[event.Name]
+	if parse == nil {
+		return nil, nil
+	}
+
+	raw, err := l.toTypesLog()
+	if err != nil {
+		return nil, err
+	}
+	filterer, err := abi.NewGnosisSafeFilterer(raw.Address, nil)
+	if err != nil {
+		panic(err)
+	}
+	decoded, err := parse(filterer, raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s log %s:%d => %v", event.Name, l.TransactionHash, raw.Index, err)
+	}
+	return &SafeEvent{
+		Name:            event.Name,
+		Address:         raw.Address.Hex(),
+		BlockHash:       l.BlockHash,
+		BlockNumber:     raw.BlockNumber,
+		TransactionHash: l.TransactionHash,
+		LogIndex:        uint64(raw.Index),
+		Event:           decoded,
+	}, nil
+}
+
+// Deprecated: use RPCClient.GetSafeEvents
+func RPCGetSafeEvents(rpc string, safes []string, from, to uint64) ([]*SafeEvent, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetSafeEvents(context.Background(), safes, from, to)
+}
+
+// GetSafeEvents scans the safe events of the addresses in the inclusive
+// block range, in chunks of at most 2000 blocks, and halves the chunk when
+// the provider rejects it for too many results or a too large range
+func (c *RPCClient) GetSafeEvents(ctx context.Context, safes []string, from, to uint64) ([]*SafeEvent, error) {
+	safeAbi, err := abi.GnosisSafeMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	var topics []string
+	for name := range safeEventParsers {
+		topics = append(topics, safeAbi.Events[name].ID.Hex())
+	}
+	sort.Strings(topics)
+	var addresses []string
+	for _, a := range safes {
+		norm := NormalizeAddress(a)
+		if norm == "" {
+			return nil, fmt.Errorf("invalid safe address %s", a)
+		}
+		addresses = append(addresses, norm)
+	}
+
+	var events []*SafeEvent
+	size := uint64(safeEventMaxRange)
+	for start := from; start <= to; {
+		end := to
+		if to-start >= size {
+			end = start + size - 1
+		}
+		filter := map[string]any{
+			"fromBlock": fmt.Sprintf("0x%x", start),
+			"toBlock":   fmt.Sprintf("0x%x", end),
+			"topics":    []any{topics},
+		}
+		if len(addresses) > 0 {
+			filter["address"] = addresses
+		}
+		res, err := c.call(ctx, "eth_getLogs", []any{filter})
+		if err != nil && ctx.Err() == nil && size > safeEventMinRange && isLogsLimitError(err) {
+			size = size / 2
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		logs, err := decodeRPCLogs(res)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range logs {
+			e, err := DecodeSafeEvent(l)
+			if err != nil {
+				return nil, err
+			}
+			if e != nil && !l.Removed {
+				events = append(events, e)
+			}
+		}
+		if end == to {
+			break
+		}
+		start = end + 1
+		size = min(size*2, safeEventMaxRange)
+	}
+	return events, nil
+}
+
+// isLogsLimitError matches the errors of providers for too many logs or too
+// large a block range, but not the timeout of the context or the client
+func isLogsLimitError(err error) bool {
+	reason := strings.ToLower(err.Error())
+	for _, s := range []string{"-32005", "block range", "more than", "too many", "too large", "response size", "exceeds limit", "is limited to", "query timeout"} {
+		if strings.Contains(reason, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *RPCLog) toTypesLog() (types.Log, error) {
+	raw := types.Log{
+		Address: common.HexToAddress(l.Address),
+		Data:    common.FromHex(l.Data),
+		TxHash:  common.HexToHash(l.TransactionHash),
+		Removed: l.Removed,
+	}
+	for _, t := range l.Topics {
+		raw.Topics = append(raw.Topics, common.HexToHash(t))
+	}
+	if l.BlockHash != "" {
+		raw.BlockHash = common.HexToHash(l.BlockHash)
+		number, err := ethereumNumberToUint64(l.BlockNumber)
+		if err != nil {
+			return raw, err
+		}
+		raw.BlockNumber = number
+	}
+	index, err := ethereumNumberToUint64(l.LogIndex)
+	if err != nil {
+		return raw, err
+	}
+	raw.Index = uint(index)
+	return raw, nil
+}
@@ -0,0 +1,121 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	safe := testSafeAddress
+	owner := "0x9d04735aaEB73535672200950fA77C2dFC86eB21"
+	safeTxHash := crypto.Keccak256([]byte("safe transaction"))
+	safeAbi, err := abi.GnosisSafeMetaData.GetAbi()
+	assert.Nil(err)
+	buildLog := func(name string, number, index int, args ...any) map[string]any {
+		event := safeAbi.Events[name]
+		data, err := event.Inputs.NonIndexed().Pack(args...)
+		assert.Nil(err)
+		return map[string]any{
+			"address":         safe,
+			"topics":          []any{event.ID.Hex()},
+			"data":            "0x" + hex.EncodeToString(data),
+			"blockHash":       fmt.Sprintf("0x%064x", number),
+			"blockNumber":     fmt.Sprintf("0x%x", number),
+			"transactionHash": fmt.Sprintf("0x%064x", number*100+index),
+			"logIndex":        fmt.Sprintf("0x%x", index),
+		}
+	}
+	logs := []map[string]any{
+		buildLog("AddedOwner", 100, 0, common.HexToAddress(owner)),
+		buildLog("ChangedGuard", 1500, 1, common.HexToAddress(EthereumSafeGuardAddress)),
+		buildLog("ExecutionSuccess", 4999, 2, [32]byte(safeTxHash), big.NewInt(0)),
+	}
+
+	var requests, rejected atomic.Int64
+	var slow atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if slow.Load() {
+			time.Sleep(200 * time.Millisecond)
+		}
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		filter := req["params"].([]any)[0].(map[string]any)
+		from, _ := new(big.Int).SetString(filter["fromBlock"].(string), 0)
+		to, _ := new(big.Int).SetString(filter["toBlock"].(string), 0)
+		res := map[string]any{"jsonrpc": "2.0", "id": req["id"]}
+		if to.Int64()-from.Int64() >= 500 {
+			rejected.Add(1)
+			res["error"] = map[string]any{"code": -32005, "message": "query returned more than 10000 results"}
+			json.NewEncoder(w).Encode(res)
+			return
+		}
+		assert.Equal([]any{safe}, filter["address"])
+		var result []any
+		for _, l := range logs {
+			n, _ := new(big.Int).SetString(l["blockNumber"].(string), 0)
+			if n.Cmp(from) >= 0 && n.Cmp(to) <= 0 {
+				result = append(result, l)
+			}
+		}
+		res["result"] = result
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	events, err := RPCGetSafeEvents(server.URL, []string{safe}, 0, 5000)
+	assert.Nil(err)
+	assert.Len(events, 3)
+	assert.Greater(rejected.Load(), int64(0))
+	assert.Less(requests.Load(), int64(40))
+
+	assert.Equal("AddedOwner", events[0].Name)
+	assert.Equal(owner, events[0].Event.(*abi.GnosisSafeAddedOwner).Owner.Hex())
+	assert.Equal("ChangedGuard", events[1].Name)
+	assert.Equal(EthereumSafeGuardAddress, events[1].Event.(*abi.GnosisSafeChangedGuard).Guard.Hex())
+	assert.Equal(uint64(1500), events[1].BlockNumber)
+	assert.Equal(uint64(1), events[1].LogIndex)
+	success := events[2].Event.(*abi.GnosisSafeExecutionSuccess)
+	assert.Equal(safeTxHash, success.TxHash[:])
+	assert.Equal(safe, events[2].Address)
+
+	_, err = RPCGetSafeEvents(server.URL, []string{"0x3e7a"}, 0, 10)
+	assert.NotNil(err)
+
+	requests.Store(0)
+	events, err = RPCGetSafeEvents(server.URL, []string{safe}, math.MaxUint64-1, math.MaxUint64)
+	assert.Nil(err)
+	assert.Len(events, 0)
+	assert.Equal(int64(1), requests.Load())
+
+	// the deadline of the context is not a logs limit to split the range
+	rpc, err := NewRPCClient(RPCConfig{Endpoint: server.URL})
+	assert.Nil(err)
+	defer rpc.Close()
+	slow.Store(true)
+	requests.Store(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = rpc.GetSafeEvents(ctx, []string{safe}, 0, 5000)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.Equal(int64(1), requests.Load())
+	unknown, err := DecodeSafeEvent(&RPCLog{Topics: []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"}})
+	assert.Nil(err)
+	assert.Nil(unknown)
+}
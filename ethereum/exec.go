@@ -0,0 +1,101 @@
+package ethereum
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	ga "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BuildSignaturesBlob concatenates the owner signatures sorted by the owner
+// address in ascending order, which is required by the Safe checkSignatures
+func (tx *SafeTransaction) BuildSignaturesBlob(owners []common.Address, threshold int64) ([]byte, error) {
+	err := tx.ValidateSignatures(owners, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	type ownerSignature struct {
+		owner common.Address
+		sig   []byte
+	}
+	var signed []*ownerSignature
+	for i, sig := range tx.Signatures {
+		if sig == nil {
+			continue
+		}
+		signed = append(signed, &ownerSignature{owners[i], normalizeSignature(sig)})
+	}
+	slices.SortFunc(signed, func(a, b *ownerSignature) int {
+		return bytes.Compare(a.owner.Bytes(), b.owner.Bytes())
+	})
+
+	var blob []byte
+	for _, s := range signed {
+		blob = append(blob, s.sig...)
+	}
+	return blob, nil
+}
+
+func (tx *SafeTransaction) BuildExecTransactionCalldata(owners []common.Address, threshold int64) ([]byte, error) {
+	signatures, err := tx.BuildSignaturesBlob(owners, threshold)
+	if err != nil {
+		return nil, err
+	}
+	safeAbi, err := ga.JSON(strings.NewReader(abi.GnosisSafeMetaData.ABI))
+	if err != nil {
+		panic(err)
+	}
+	return safeAbi.Pack(
+		"execTransaction",
+		tx.Destination,
+		tx.Value,
+		tx.Data,
+		tx.Operation,
+		tx.SafeTxGas,
+		tx.BaseGas,
+		tx.GasPrice,
+		tx.GasToken,
+		tx.RefundReceiver,
+		signatures,
+	)
+}
+
+// BuildExecTransaction returns the unsigned EIP-1559 transaction calling the
+// Safe execTransaction, any funded key could sign and send it
+func (tx *SafeTransaction) BuildExecTransaction(owners []common.Address, threshold int64, nonce, gas uint64, gasTipCap, gasFeeCap *big.Int) (*types.Transaction, error) {
+	if gas == 0 || gasTipCap == nil || gasFeeCap == nil || gasFeeCap.Cmp(gasTipCap) < 0 {
+		return nil, fmt.Errorf("invalid gas %d tip %v fee %v", gas, gasTipCap, gasFeeCap)
+	}
+	data, err := tx.BuildExecTransactionCalldata(owners, threshold)
+	if err != nil {
+		return nil, err
+	}
+	safe := common.HexToAddress(tx.SafeAddress)
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(tx.ChainID),
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gas,
+		To:        &safe,
+		Value:     big.NewInt(0),
+		Data:      data,
+	}), nil
+}
+
+// normalizeSignature converts the recovery id 0 or 1 from crypto.Sign to the
+// eth_sign v expected by the Safe, the same as ProcessSignature
+func normalizeSignature(sig []byte) []byte {
+	sig = bytes.Clone(sig)
+	if len(sig) == 65 && sig[64] < 27 {
+		sig = ProcessSignature(sig)
+	}
+	return sig
+}
@@ -0,0 +1,60 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthExecTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	keys, owners := testOwners(3)
+	tx := testTransfer(t, 137)
+	_, err := tx.BuildExecTransactionCalldata(owners, 2)
+	assert.NotNil(err)
+
+	hash, err := HashMessageForSignature(hex.EncodeToString(tx.Message))
+	assert.Nil(err)
+	for _, i := range []int{0, 2} {
+		sig, err := crypto.Sign(hash, keys[i])
+		assert.Nil(err)
+		err = tx.AddOwnerSignature(owners, owners[i], sig)
+		assert.Nil(err)
+	}
+	data, err := tx.BuildExecTransactionCalldata(owners, 2)
+	assert.Nil(err)
+
+	safeAbi, err := abi.GnosisSafeMetaData.GetAbi()
+	assert.Nil(err)
+	args, err := safeAbi.Methods["execTransaction"].Inputs.Unpack(data[4:])
+	assert.Nil(err)
+	signatures := args[9].([]byte)
+	assert.Len(signatures, 130)
+	first, second := owners[0], owners[2]
+	if bytes.Compare(first.Bytes(), second.Bytes()) > 0 {
+		first, second = second, first
+	}
+	for i, o := range []common.Address{first, second} {
+		sig := signatures[i*65 : i*65+65]
+		assert.True(sig[64] == 31 || sig[64] == 32)
+		signer, err := tx.RecoverSigner(sig)
+		assert.Nil(err)
+		assert.Equal(o, signer)
+	}
+
+	etx, err := tx.BuildExecTransaction(owners, 2, 7, 200000, big.NewInt(1000000000), big.NewInt(30000000000))
+	assert.Nil(err)
+	assert.Equal(uint8(2), etx.Type())
+	assert.Equal(int64(137), etx.ChainId().Int64())
+	assert.Equal(common.HexToAddress(tx.SafeAddress), *etx.To())
+	assert.Equal(data, etx.Data())
+	_, err = tx.BuildExecTransaction(owners, 2, 7, 200000, big.NewInt(2), big.NewInt(1))
+	assert.NotNil(err)
+}
@@ -0,0 +1,141 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-1271 messages of the Safe CompatibilityFallbackHandler
+// https://github.com/safe-global/safe-contracts/blob/v1.3.0/contracts/handler/CompatibilityFallbackHandler.sol
+
+const safeMessageTypehash = "0x60b3cbf8b4a223d68d641b3b6ddf9a298e7f33710cf3d3a9d1146b5a6150fbca"
+
+func HashTypedData(typed apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		return nil, fmt.Errorf("apitypes.TypedDataAndHash() => %v", err)
+	}
+	return hash, nil
+}
+
+// GetSafeMessageHash returns the hash signed by owners for the message, the
+// same as getMessageHash of the Safe fallback handler
+func GetSafeMessageHash(chainID int64, safeAddress string, message []byte) []byte {
+	typehash, err := hex.DecodeString(safeMessageTypehash[2:])
+	if err != nil {
+		panic(err)
+	}
+	structHash := crypto.Keccak256(typehash, crypto.Keccak256(message))
+	domain := packDomainSeparatorArguments(chainID, safeAddress)
+	domainSeparator := crypto.Keccak256(domain)
+	return crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash)
+}
+
+// GetSafeTypedDataHash wraps the EIP-712 hash of the typed data in a
+// SafeMessage, which is verified by isValidSignature(bytes32,bytes)
+func GetSafeTypedDataHash(chainID int64, safeAddress string, typed apitypes.TypedData) ([]byte, error) {
+	hash, err := HashTypedData(typed)
+	if err != nil {
+		return nil, err
+	}
+	return GetSafeMessageHash(chainID, safeAddress, hash), nil
+}
+
+// SignSafeMessageHash signs the safe message hash directly with v = 27 or 28
+func SignSafeMessageHash(hash []byte, priv string) ([]byte, error) {
+	private, err := crypto.HexToECDSA(priv)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(hash, private)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// BuildSafeMessageSignatures concatenates the owner signatures of the safe
+// message hash, sorted by the signer address as required by the Safe
+func BuildSafeMessageSignatures(hash []byte, sigs [][]byte) ([]byte, error) {
+	signers := make(map[common.Address][]byte)
+	var addrs []common.Address
+	for _, sig := range sigs {
+		signer, err := recoverHashSigner(hash, sig)
+		if err != nil {
+			return nil, err
+		}
+		if signers[signer] != nil {
+			return nil, fmt.Errorf("duplicated signer %s", signer.Hex())
+		}
+		signers[signer] = normalizeSignature(sig)
+		addrs = append(addrs, signer)
+	}
+	slices.SortFunc(addrs, func(a, b common.Address) int {
+		return bytes.Compare(a.Bytes(), b.Bytes())
+	})
+	var blob []byte
+	for _, a := range addrs {
+		blob = append(blob, signers[a]...)
+	}
+	return blob, nil
+}
+
+// VerifySafeMessageSignatures checks the signatures blob in the same way as
+// checkNSignatures of the Safe, with ascending and distinct owner signers
+func VerifySafeMessageSignatures(hash, signatures []byte, owners []common.Address, threshold int64) error {
+	if threshold < 1 || threshold > int64(len(owners)) {
+		return fmt.Errorf("invalid threshold %d for owners %d", threshold, len(owners))
+	}
+	if int64(len(signatures)) < threshold*65 || len(signatures)%65 != 0 {
+		return fmt.Errorf("invalid signatures size %d for threshold %d", len(signatures), threshold)
+	}
+	var last common.Address
+	for i := int64(0); i < threshold; i++ {
+		signer, err := recoverHashSigner(hash, signatures[i*65:i*65+65])
+		if err != nil {
+			return err
+		}
+		if bytes.Compare(signer.Bytes(), last.Bytes()) <= 0 {
+			return fmt.Errorf("invalid signer %s order", signer.Hex())
+		}
+		if !slices.Contains(owners, signer) {
+			return fmt.Errorf("invalid signer %s not owner", signer.Hex())
+		}
+		last = signer
+	}
+	return nil
+}
+
+// recoverHashSigner supports both the eth_sign signature with v = 31 or 32,
+// and the plain signature of the hash with v = 27 or 28, the recovery id from
+// crypto.Sign is treated as eth_sign
+func recoverHashSigner(hash, sig []byte) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, fmt.Errorf("invalid signature %x", sig)
+	}
+	v := normalizeSignature(sig)[64]
+	switch v {
+	case 31, 32:
+		h, err := HashMessageForSignature(hex.EncodeToString(hash))
+		if err != nil {
+			return common.Address{}, err
+		}
+		hash, v = h, v-4
+	case 27, 28:
+	default:
+		return common.Address{}, fmt.Errorf("invalid signature v %d", v)
+	}
+	rs := append(bytes.Clone(sig[:64]), v-27)
+	pub, err := crypto.SigToPub(hash, rs)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
@@ -0,0 +1,56 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeTypedMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	mail := `{"types":{"EIP712Domain":[{"name":"name","type":"string"},{"name":"version","type":"string"},{"name":"chainId","type":"uint256"},{"name":"verifyingContract","type":"address"}],"Person":[{"name":"name","type":"string"},{"name":"wallet","type":"address"}],"Mail":[{"name":"from","type":"Person"},{"name":"to","type":"Person"},{"name":"contents","type":"string"}]},"primaryType":"Mail","domain":{"name":"Ether Mail","version":"1","chainId":"1","verifyingContract":"0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},"message":{"from":{"name":"Cow","wallet":"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},"to":{"name":"Bob","wallet":"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},"contents":"Hello, Bob!"}}`
+	var typed apitypes.TypedData
+	err := json.Unmarshal([]byte(mail), &typed)
+	assert.Nil(err)
+	hash, err := HashTypedData(typed)
+	assert.Nil(err)
+	assert.Equal("be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hex.EncodeToString(hash))
+
+	safeHash, err := GetSafeTypedDataHash(137, testSafeAddress, typed)
+	assert.Nil(err)
+	wrapped, err := HashTypedData(apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}},
+			"SafeMessage":  {{Name: "message", Type: "bytes"}},
+		},
+		PrimaryType: "SafeMessage",
+		Domain:      apitypes.TypedDataDomain{ChainId: math.NewHexOrDecimal256(137), VerifyingContract: testSafeAddress},
+		Message:     apitypes.TypedDataMessage{"message": hash},
+	})
+	assert.Nil(err)
+	assert.Equal(wrapped, safeHash)
+
+	keys, owners := testOwners(3)
+	var sigs [][]byte
+	for _, key := range keys[1:] {
+		sig, err := SignSafeMessageHash(safeHash, hex.EncodeToString(crypto.FromECDSA(key)))
+		assert.Nil(err)
+		sigs = append(sigs, sig)
+	}
+	blob, err := BuildSafeMessageSignatures(safeHash, sigs)
+	assert.Nil(err)
+	assert.Nil(VerifySafeMessageSignatures(safeHash, blob, owners, 2))
+	assert.NotNil(VerifySafeMessageSignatures(safeHash, blob, owners, 3))
+	assert.NotNil(VerifySafeMessageSignatures(safeHash, blob, owners[:2], 2))
+	reversed := append(bytes.Clone(blob[65:]), blob[:65]...)
+	assert.NotNil(VerifySafeMessageSignatures(safeHash, reversed, owners, 2))
+	_, err = BuildSafeMessageSignatures(safeHash, [][]byte{sigs[0], sigs[0]})
+	assert.NotNil(err)
+}
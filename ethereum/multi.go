@@ -0,0 +1,144 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"strings"
+
+	sc "github.com/MixinNetwork/go-safe-sdk/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var _ ScannerRPC = (*MultiRPCClient)(nil)
+
+// MultiRPCClient fails over among several endpoints of the same chain, and
+// with Quorum the security sensitive reads must be agreed by a majority
+type MultiRPCClient struct {
+	*sc.RPCPool[*RPCClient]
+	clients []*RPCClient
+
+	Quorum bool
+}
+
+func NewMultiRPCClient(configs ...RPCConfig) (*MultiRPCClient, error) {
+	var clients []*RPCClient
+	for _, config := range configs {
+		c, err := NewRPCClient(config)
+		if err != nil {
+			return nil, err
+		}
+		c.failover = true
+		clients = append(clients, c)
+	}
+	pool, err := sc.NewRPCPool(clients, func(ctx context.Context, c *RPCClient) (int64, error) {
+		return c.GetBlockHeight(ctx)
+	}, isEndpointError)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiRPCClient{RPCPool: pool, clients: clients}, nil
+}
+
+func (m *MultiRPCClient) Close() {
+	for _, c := range m.clients {
+		c.Close()
+	}
+}
+
+// GetBlockHeight checks the health of all endpoints, and returns the highest
+// height of those not lagging
+func (m *MultiRPCClient) GetBlockHeight(ctx context.Context) (int64, error) {
+	return m.CheckHealth(ctx)
+}
+
+func (m *MultiRPCClient) GetBlockHash(ctx context.Context, height int64) (string, error) {
+	return sc.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (string, error) {
+		return c.GetBlockHash(ctx, height)
+	})
+}
+
+func (m *MultiRPCClient) GetBlockWithTransactions(ctx context.Context, hash string) (*RPCBlockWithTransactions, error) {
+	return sc.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (*RPCBlockWithTransactions, error) {
+		return c.GetBlockWithTransactions(ctx, hash)
+	})
+}
+
+func (m *MultiRPCClient) DebugTraceBlockByHash(ctx context.Context, hash string) ([]*RPCBlockCallTrace, error) {
+	return sc.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) ([]*RPCBlockCallTrace, error) {
+		return c.DebugTraceBlockByHash(ctx, hash)
+	})
+}
+
+func (m *MultiRPCClient) GetLogs(ctx context.Context, filter map[string]any) ([]*RPCLog, error) {
+	return sc.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) ([]*RPCLog, error) {
+		return c.GetLogs(ctx, filter)
+	})
+}
+
+func (m *MultiRPCClient) GetTransactionByHash(ctx context.Context, hash string) (*RPCTransaction, error) {
+	return sc.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (*RPCTransaction, error) {
+		return c.GetTransactionByHash(ctx, hash)
+	})
+}
+
+func (m *MultiRPCClient) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	return sc.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (*big.Int, error) {
+		return c.GetGasPrice(ctx)
+	})
+}
+
+func (m *MultiRPCClient) GetThreshold(ctx context.Context, address string) (int64, error) {
+	return sc.RPCFailover(ctx, m.RPCPool, func(ctx context.Context, c *RPCClient) (int64, error) {
+		return c.GetThreshold(ctx, address)
+	})
+}
+
+// FetchSafeNonce should be at a height for the quorum, because the endpoints
+// may be at different latest blocks
+func (m *MultiRPCClient) FetchSafeNonce(ctx context.Context, address string, height int64) (int64, error) {
+	return quorumRead(ctx, m, func(ctx context.Context, c *RPCClient) (int64, error) {
+		return c.FetchSafeNonce(ctx, address, height)
+	})
+}
+
+func (m *MultiRPCClient) GetOwners(ctx context.Context, address string) ([]common.Address, error) {
+	return quorumRead(ctx, m, func(ctx context.Context, c *RPCClient) ([]common.Address, error) {
+		return c.GetOwners(ctx, address)
+	})
+}
+
+func (m *MultiRPCClient) GetAddressBalanceAtBlock(ctx context.Context, blockHash, address string) (*big.Int, error) {
+	return quorumRead(ctx, m, func(ctx context.Context, c *RPCClient) (*big.Int, error) {
+		return c.GetAddressBalanceAtBlock(ctx, blockHash, address)
+	})
+}
+
+func quorumRead[T any](ctx context.Context, m *MultiRPCClient, fn func(context.Context, *RPCClient) (T, error)) (T, error) {
+	if m.Quorum {
+		return sc.RPCQuorum(ctx, m.RPCPool, fn)
+	}
+	return sc.RPCFailover(ctx, m.RPCPool, fn)
+}
+
+// isEndpointError is true for the network and server errors, or when the
+// provider limits the requests, but not for the errors of the call, e.g. a
+// reverted contract call or an unknown transaction
+func isEndpointError(err error) bool {
+	var ue *url.Error
+	var se *json.SyntaxError
+	var he rpc.HTTPError
+	var re rpc.Error
+	switch {
+	case errors.As(err, &ue), errors.As(err, &se), errors.As(err, &he):
+		return true
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.As(err, &re):
+		return re.ErrorCode() == -32005
+	}
+	return strings.Contains(err.Error(), "-32005")
+}
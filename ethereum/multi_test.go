@@ -0,0 +1,121 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthMultiRPCClient(t *testing.T) {
+	assert := assert.New(t)
+
+	balances := make([]*atomic.Int64, 3)
+	downs, reverted, hung := make([]*atomic.Bool, 3), make([]*atomic.Bool, 3), make([]*atomic.Bool, 3)
+	var configs []RPCConfig
+	for i, height := range []string{"0x10", "0xf", "0x5"} {
+		balances[i], downs[i], reverted[i], hung[i] = new(atomic.Int64), new(atomic.Bool), new(atomic.Bool), new(atomic.Bool)
+		balances[i].Store(int64(i + 1))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hung[i].Load() {
+				io.Copy(io.Discard, r.Body)
+				<-r.Context().Done()
+				return
+			}
+			if downs[i].Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			var req map[string]any
+			err := json.NewDecoder(r.Body).Decode(&req)
+			assert.Nil(err)
+			res := map[string]any{"jsonrpc": "2.0", "id": req["id"]}
+			switch {
+			case req["method"] == "eth_blockNumber":
+				res["result"] = height
+			case reverted[i].Load():
+				res["error"] = map[string]any{"code": 3, "message": "execution reverted"}
+			case req["method"] == "eth_getBalance":
+				res["result"] = fmt.Sprintf("0x%x", balances[i].Load())
+			}
+			json.NewEncoder(w).Encode(res)
+		}))
+		defer server.Close()
+		configs = append(configs, RPCConfig{Endpoint: server.URL})
+	}
+	_, err := NewMultiRPCClient()
+	assert.NotNil(err)
+	rpc, err := NewMultiRPCClient(configs...)
+	assert.Nil(err)
+	defer rpc.Close()
+
+	ctx := context.Background()
+	height, err := rpc.GetBlockHeight(ctx)
+	assert.Nil(err)
+	assert.Equal(int64(16), height)
+
+	address := testReceiverAddress
+
+	balance, err := rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.Nil(err)
+	assert.Equal(int64(1), balance.Int64())
+	downs[0].Store(true)
+	balance, err = rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.Nil(err)
+	assert.Equal(int64(2), balance.Int64())
+	downs[0].Store(false)
+
+	rpc.Quorum = true
+	_, err = rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.ErrorContains(err, "no rpc quorum")
+	balances[2].Store(2)
+	balance, err = rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.Nil(err)
+	assert.Equal(int64(2), balance.Int64())
+	downs[1].Store(true)
+	_, err = rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.ErrorContains(err, "no rpc quorum")
+
+	downs[0].Store(true)
+	_, err = rpc.GetBlockHeight(ctx)
+	assert.Nil(err)
+	downs[2].Store(true)
+	_, err = rpc.GetBlockHeight(ctx)
+	assert.ErrorContains(err, "no healthy rpc endpoints")
+
+	// the error of the call doesn't mark the endpoint unhealthy
+	for _, down := range downs {
+		down.Store(false)
+	}
+	rpc.Quorum = false
+	_, err = rpc.GetBlockHeight(ctx)
+	assert.Nil(err)
+	reverted[0].Store(true)
+	balance, err = rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.Nil(err)
+	assert.Equal(int64(2), balance.Int64())
+	reverted[0].Store(false)
+	balance, err = rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.Nil(err)
+	assert.Equal(int64(1), balance.Int64())
+
+	// the hung endpoint is canceled after the timeout of each call
+	rpc.Timeout = 100 * time.Millisecond
+	hung[0].Store(true)
+	start := time.Now()
+	balance, err = rpc.GetAddressBalanceAtBlock(ctx, "0xb1", address)
+	assert.Nil(err)
+	assert.Equal(int64(2), balance.Int64())
+	height, err = rpc.GetBlockHeight(ctx)
+	assert.Nil(err)
+	assert.Equal(int64(15), height)
+	assert.Less(time.Since(start), time.Second)
+	hung[0].Store(false)
+}
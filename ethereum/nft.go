@@ -0,0 +1,128 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ga "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	TokenStandardERC721  = 721
+	TokenStandardERC1155 = 1155
+
+	erc721ABI  = `[{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"name":"safeTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	erc1155ABI = `[{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}],"name":"safeTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"ids","type":"uint256[]"},{"name":"amounts","type":"uint256[]"},{"name":"data","type":"bytes"}],"name":"safeBatchTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+)
+
+// CreateNFTTransaction transfers the ERC721 or ERC1155 tokens of the output
+// from the safe, multiple ERC1155 token ids are sent in a batch transfer
+func CreateNFTTransaction(ctx context.Context, chainID int64, id, safeAddress string, o *Output, nonce *big.Int) (*SafeTransaction, error) {
+	if nonce == nil {
+		return nil, fmt.Errorf("Invalid ethereum transaction nonce")
+	}
+	data, err := GetNFTTxData(safeAddress, o)
+	if err != nil {
+		return nil, err
+	}
+	tx := &SafeTransaction{
+		ChainID:        chainID,
+		SafeAddress:    safeAddress,
+		Destination:    common.HexToAddress(o.TokenAddress),
+		Value:          big.NewInt(0),
+		Data:           data,
+		Operation:      operationTypeCall,
+		SafeTxGas:      big.NewInt(0),
+		BaseGas:        big.NewInt(0),
+		GasPrice:       big.NewInt(0),
+		GasToken:       common.HexToAddress(EthereumEmptyAddress),
+		RefundReceiver: common.HexToAddress(EthereumEmptyAddress),
+		Nonce:          nonce,
+	}
+	tx.Message = tx.GetTransactionHash()
+	tx.TxHash = tx.Hash(id)
+	return tx, nil
+}
+
+func GetNFTTxData(safeAddress string, o *Output) ([]byte, error) {
+	if NormalizeAddress(o.TokenAddress) == "" {
+		return nil, fmt.Errorf("invalid NFT address %s", o.TokenAddress)
+	}
+	if len(o.TokenIds) == 0 {
+		return nil, fmt.Errorf("invalid NFT output without token ids")
+	}
+	from, to := common.HexToAddress(safeAddress), common.HexToAddress(o.Destination)
+	switch o.TokenStandard {
+	case TokenStandardERC721:
+		if len(o.TokenIds) != 1 {
+			return nil, fmt.Errorf("invalid ERC721 token ids count %d", len(o.TokenIds))
+		}
+		return nftABI(erc721ABI).Pack("safeTransferFrom", from, to, o.TokenIds[0])
+	case TokenStandardERC1155:
+		if len(o.TokenIds) != len(o.TokenAmounts) {
+			return nil, fmt.Errorf("invalid ERC1155 token ids %d and amounts %d", len(o.TokenIds), len(o.TokenAmounts))
+		}
+		if len(o.TokenIds) == 1 {
+			return nftABI(erc1155ABI).Pack("safeTransferFrom", from, to, o.TokenIds[0], o.TokenAmounts[0], []byte{})
+		}
+		return nftABI(erc1155ABI).Pack("safeBatchTransferFrom", from, to, o.TokenIds, o.TokenAmounts, []byte{})
+	default:
+		return nil, fmt.Errorf("invalid NFT standard %d", o.TokenStandard)
+	}
+}
+
+// parseNFTOutput decodes the NFT transfer calldata sent to the token, and
+// returns nil if the data is not a known NFT transfer from the safe
+func parseNFTOutput(safe, token common.Address, data []byte) *Output {
+	if len(data) < 4 {
+		return nil
+	}
+	for _, def := range []string{erc721ABI, erc1155ABI} {
+		parsed := nftABI(def)
+		method, err := parsed.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil || args[0].(common.Address) != safe {
+			return nil
+		}
+		o := &Output{
+			TokenAddress: token.Hex(),
+			Destination:  args[1].(common.Address).Hex(),
+		}
+		switch {
+		case def == erc721ABI:
+			o.TokenStandard = TokenStandardERC721
+			o.TokenIds = []*big.Int{args[2].(*big.Int)}
+			o.Amount = big.NewInt(1)
+		case method.Name == "safeTransferFrom":
+			o.TokenStandard = TokenStandardERC1155
+			o.TokenIds = []*big.Int{args[2].(*big.Int)}
+			o.TokenAmounts = []*big.Int{args[3].(*big.Int)}
+		default:
+			o.TokenStandard = TokenStandardERC1155
+			o.TokenIds = args[2].([]*big.Int)
+			o.TokenAmounts = args[3].([]*big.Int)
+		}
+		if o.TokenStandard == TokenStandardERC1155 {
+			o.Amount = big.NewInt(0)
+			for _, a := range o.TokenAmounts {
+				o.Amount.Add(o.Amount, a)
+			}
+		}
+		return o
+	}
+	return nil
+}
+
+func nftABI(def string) ga.ABI {
+	parsed, err := ga.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
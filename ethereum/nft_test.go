@@ -0,0 +1,87 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthNFTTx(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	erc721 := &Output{
+		TokenAddress:  "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+		Destination:   testReceiverAddress,
+		TokenStandard: TokenStandardERC721,
+		TokenIds:      []*big.Int{big.NewInt(8817)},
+	}
+	tx, err := CreateTransactionFromOutputs(ctx, TypeETHTx, 137, testTransactionId, testSafeAddress, []*Output{erc721}, big.NewInt(1))
+	assert.Nil(err)
+	assert.Equal("42842e0e", hex.EncodeToString(tx.Data[:4]))
+	os, err := tx.ExtractOutputs()
+	assert.Nil(err)
+	assert.Len(os, 1)
+	assert.Equal(TokenStandardERC721, os[0].TokenStandard)
+	assert.Equal(erc721.TokenAddress, os[0].TokenAddress)
+	assert.Equal(testReceiverAddress, os[0].Destination)
+	assert.Equal(big.NewInt(8817), os[0].TokenIds[0])
+	st, err := UnmarshalSafeTransaction(tx.Marshal())
+	assert.Nil(err)
+	assert.Equal(tx.Message, st.Message)
+
+	erc1155 := &Output{
+		TokenAddress:  "0x76BE3b62873462d2142405439777e971754E8E77",
+		Destination:   testReceiverAddress,
+		TokenStandard: TokenStandardERC1155,
+		TokenIds:      []*big.Int{big.NewInt(1), big.NewInt(2)},
+		TokenAmounts:  []*big.Int{big.NewInt(10), big.NewInt(20)},
+	}
+	single := &Output{
+		TokenAddress:  erc1155.TokenAddress,
+		Destination:   testReceiverAddress,
+		TokenStandard: TokenStandardERC1155,
+		TokenIds:      []*big.Int{big.NewInt(3)},
+		TokenAmounts:  []*big.Int{big.NewInt(5)},
+	}
+	native := &Output{
+		TokenAddress: EthereumEmptyAddress,
+		Destination:  testReceiverAddress,
+		Amount:       big.NewInt(1000),
+	}
+	tx, err = CreateTransactionFromOutputs(ctx, TypeMultiSendTx, 137, testTransactionId, testSafeAddress, []*Output{erc721, erc1155, single, native}, big.NewInt(2))
+	assert.Nil(err)
+	os, err = tx.ExtractOutputs()
+	assert.Nil(err)
+	assert.Len(os, 4)
+	assert.Equal(TokenStandardERC721, os[0].TokenStandard)
+	assert.Equal(TokenStandardERC1155, os[1].TokenStandard)
+	assert.Equal(erc1155.TokenIds, os[1].TokenIds)
+	assert.Equal(erc1155.TokenAmounts, os[1].TokenAmounts)
+	assert.Equal(big.NewInt(30), os[1].Amount)
+	assert.Equal(big.NewInt(5), os[2].TokenAmounts[0])
+	assert.Equal(big.NewInt(1000), os[3].Amount)
+
+	actions, err := tx.DecodeActions()
+	assert.Nil(err)
+	assert.Len(actions, 4)
+	assert.Equal("safeTransferFrom", actions[0].Method)
+	assert.Equal("safeBatchTransferFrom", actions[1].Method)
+	assert.Equal(common.HexToAddress(testSafeAddress), actions[1].Args["from"])
+	assert.Equal("safeTransferFrom", actions[2].Method)
+
+	other := *tx
+	other.SafeAddress = testReceiverAddress
+	_, err = other.ExtractOutputs()
+	assert.NotNil(err)
+
+	erc721.TokenIds = append(erc721.TokenIds, big.NewInt(1))
+	_, err = CreateNFTTransaction(ctx, 137, testTransactionId, testSafeAddress, erc721, big.NewInt(1))
+	assert.NotNil(err)
+	_, err = GetSafeMultiSendData("", []*Output{single})
+	assert.NotNil(err)
+}
@@ -0,0 +1,317 @@
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	ge "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	relayerFeeHistoryBlocks     = 10
+	relayerFeeHistoryPercentile = 50
+	relayerGasLimitPercent      = 120
+	relayerFeeBumpPercent       = 125
+	relayerMinimumTip           = 1000000000
+	relayerNonceRetries         = 3
+)
+
+// RelayerBackend is implemented by both ethclient.Client and the client
+// of the go-ethereum simulated backend
+type RelayerBackend interface {
+	ge.ChainIDReader
+	ge.GasEstimator
+	ge.FeeHistoryReader
+	ge.TransactionSender
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+var _ RelayerBackend = (*ethclient.Client)(nil)
+
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+type privateKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewPrivateKeySigner(priv string) (Signer, error) {
+	key, err := crypto.HexToECDSA(priv)
+	if err != nil {
+		return nil, err
+	}
+	return &privateKeySigner{key: key}, nil
+}
+
+func (s *privateKeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *privateKeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// Relayer submits fully signed safe transactions with a funded sender, and
+// rebroadcasts them with bumped fees until they are included
+type Relayer struct {
+	backend RelayerBackend
+	signer  Signer
+	chainID *big.Int
+
+	nonceLock sync.Mutex
+	nonce     *uint64
+
+	PollInterval        time.Duration
+	RebroadcastInterval time.Duration
+	// Tick returns the channel to poll the receipts again, it is time.After
+	// of PollInterval by default, and the fees are bumped after the polls of
+	// RebroadcastInterval
+	Tick func() <-chan time.Time
+}
+
+func NewRelayer(ctx context.Context, backend RelayerBackend, signer Signer) (*Relayer, error) {
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Relayer{
+		backend:             backend,
+		signer:              signer,
+		chainID:             chainID,
+		PollInterval:        3 * time.Second,
+		RebroadcastInterval: time.Minute,
+	}, nil
+}
+
+func (r *Relayer) Address() common.Address {
+	return r.signer.Address()
+}
+
+// Relay executes the safe transaction, and returns the receipt after it is
+// included, the receipt is returned with an error if the execution failed
+func (r *Relayer) Relay(ctx context.Context, tx *SafeTransaction, owners []common.Address, threshold int64) (*types.Receipt, error) {
+	if tx.ChainID != r.chainID.Int64() {
+		return nil, fmt.Errorf("invalid safe transaction chain %d for relayer %d", tx.ChainID, r.chainID)
+	}
+	data, err := tx.BuildExecTransactionCalldata(owners, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return r.Send(ctx, common.HexToAddress(tx.SafeAddress), data)
+}
+
+func (r *Relayer) Send(ctx context.Context, to common.Address, data []byte) (*types.Receipt, error) {
+	gas, err := r.backend.EstimateGas(ctx, ge.CallMsg{
+		From: r.signer.Address(),
+		To:   &to,
+		Data: data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("EstimateGas(%s) => %v", to.Hex(), err)
+	}
+	gas = gas * relayerGasLimitPercent / 100
+	tip, feeCap, err := r.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := r.nextNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []common.Hash
+	for retries := 0; ; {
+		signed, err := r.signer.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   r.chainID,
+			Nonce:     nonce,
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       gas,
+			To:        &to,
+			Value:     big.NewInt(0),
+			Data:      data,
+		}), r.chainID)
+		if err != nil {
+			r.resetNonce()
+			return nil, err
+		}
+		err = r.backend.SendTransaction(ctx, signed)
+		switch {
+		case err == nil:
+			hashes = append(hashes, signed.Hash())
+		case isTransactionKnownError(err):
+			if !slices.Contains(hashes, signed.Hash()) {
+				hashes = append(hashes, signed.Hash())
+			}
+		case isNonceTooLowError(err) && retries < relayerNonceRetries:
+			// the nonce is used by a previous broadcast already included,
+			// or by another transaction of the sender
+			retries += 1
+			receipt, err := r.receipt(ctx, hashes)
+			if err != nil {
+				r.resetNonce()
+				return nil, err
+			}
+			if receipt != nil {
+				return checkReceipt(receipt)
+			}
+			r.resetNonce()
+			nonce, err = r.nextNonce(ctx)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		default:
+			r.resetNonce()
+			return nil, fmt.Errorf("SendTransaction(%s) => %v", signed.Hash().Hex(), err)
+		}
+
+		receipt, err := r.waitReceipt(ctx, hashes)
+		if err != nil {
+			r.resetNonce()
+			return nil, err
+		}
+		if receipt != nil {
+			return checkReceipt(receipt)
+		}
+
+		tip = bumpFee(tip)
+		feeCap = bumpFee(feeCap)
+		_, latest, err := r.SuggestFees(ctx)
+		if err == nil && latest.Cmp(feeCap) > 0 {
+			feeCap = latest
+		}
+	}
+}
+
+// SuggestFees returns the priority fee from the median rewards of recent
+// blocks, and the fee cap allows the base fee to double
+func (r *Relayer) SuggestFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	history, err := r.backend.FeeHistory(ctx, relayerFeeHistoryBlocks, nil, []float64{relayerFeeHistoryPercentile})
+	if err != nil {
+		return nil, nil, fmt.Errorf("FeeHistory() => %v", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, nil, fmt.Errorf("FeeHistory() => empty base fee")
+	}
+	tip, count := big.NewInt(0), int64(0)
+	for _, rewards := range history.Reward {
+		if len(rewards) == 0 || rewards[0] == nil {
+			continue
+		}
+		tip.Add(tip, rewards[0])
+		count += 1
+	}
+	if count > 0 {
+		tip.Div(tip, big.NewInt(count))
+	}
+	if tip.Cmp(big.NewInt(relayerMinimumTip)) < 0 {
+		tip = big.NewInt(relayerMinimumTip)
+	}
+	base := history.BaseFee[len(history.BaseFee)-1]
+	feeCap := new(big.Int).Mul(base, big.NewInt(2))
+	feeCap.Add(feeCap, tip)
+	return tip, feeCap, nil
+}
+
+func (r *Relayer) waitReceipt(ctx context.Context, hashes []common.Hash) (*types.Receipt, error) {
+	polls := max(int64(r.RebroadcastInterval/r.PollInterval), 1)
+	for i := int64(0); ; i++ {
+		receipt, err := r.receipt(ctx, hashes)
+		if err != nil || receipt != nil {
+			return receipt, err
+		}
+		if i >= polls {
+			return nil, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-r.tick():
+		}
+	}
+}
+
+// receipt returns the receipt of any broadcast, or nil if none is included
+func (r *Relayer) receipt(ctx context.Context, hashes []common.Hash) (*types.Receipt, error) {
+	for _, h := range hashes {
+		receipt, err := r.backend.TransactionReceipt(ctx, h)
+		if err == nil {
+			return receipt, nil
+		}
+		if !isReceiptNotFoundError(err) {
+			return nil, fmt.Errorf("TransactionReceipt(%s) => %v", h.Hex(), err)
+		}
+	}
+	return nil, nil
+}
+
+func checkReceipt(receipt *types.Receipt) (*types.Receipt, error) {
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("transaction %s reverted", receipt.TxHash.Hex())
+	}
+	return receipt, nil
+}
+
+func (r *Relayer) tick() <-chan time.Time {
+	if r.Tick != nil {
+		return r.Tick()
+	}
+	return time.After(r.PollInterval)
+}
+
+func (r *Relayer) nextNonce(ctx context.Context) (uint64, error) {
+	r.nonceLock.Lock()
+	defer r.nonceLock.Unlock()
+
+	if r.nonce == nil {
+		nonce, err := r.backend.PendingNonceAt(ctx, r.signer.Address())
+		if err != nil {
+			return 0, fmt.Errorf("PendingNonceAt(%s) => %v", r.signer.Address().Hex(), err)
+		}
+		r.nonce = &nonce
+	}
+	nonce := *r.nonce
+	*r.nonce = nonce + 1
+	return nonce, nil
+}
+
+// the nonce is fetched from the pending state again after any failure
+func (r *Relayer) resetNonce() {
+	r.nonceLock.Lock()
+	defer r.nonceLock.Unlock()
+	r.nonce = nil
+}
+
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(relayerFeeBumpPercent))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// the same transaction is broadcast again before the fees are bumped
+func isTransactionKnownError(err error) bool {
+	return strings.Contains(err.Error(), "already known")
+}
+
+func isNonceTooLowError(err error) bool {
+	return strings.Contains(err.Error(), "nonce too low")
+}
+
+// geth reports the receipts not indexed yet as an error instead of null
+func isReceiptNotFoundError(err error) bool {
+	return errors.Is(err, ge.NotFound) || strings.Contains(err.Error(), "transaction indexing is in progress")
+}
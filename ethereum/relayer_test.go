@@ -0,0 +1,156 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthRelayer(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		sender: {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))},
+	})
+	defer backend.Close()
+	client := backend.Client()
+
+	signer, err := NewPrivateKeySigner(hex.EncodeToString(crypto.FromECDSA(key)))
+	assert.Nil(err)
+	relayer, err := NewRelayer(ctx, client, signer)
+	assert.Nil(err)
+	relayer.PollInterval = time.Second
+	relayer.RebroadcastInterval = 2 * time.Second
+	ticks := make(chan chan time.Time)
+	relayer.Tick = func() <-chan time.Time {
+		tick := make(chan time.Time, 1)
+		ticks <- tick
+		return tick
+	}
+	assert.Equal(sender, relayer.Address())
+
+	tip, feeCap, err := relayer.SuggestFees(ctx)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1000000000), tip)
+	assert.True(feeCap.Cmp(tip) > 0)
+
+	tx := testTransfer(t, 137)
+	_, err = relayer.Relay(ctx, tx, []common.Address{sender}, 1)
+	assert.NotNil(err)
+
+	type result struct {
+		receipt *types.Receipt
+		err     error
+	}
+	results := make(chan result)
+	receiver := common.HexToAddress(testReceiverAddress)
+	go func() {
+		receipt, err := relayer.Send(ctx, receiver, []byte{1, 2, 3})
+		results <- result{receipt, err}
+	}()
+	// the first broadcast is not mined in the polls of the rebroadcast interval
+	for range 2 {
+		select {
+		case r := <-results:
+			t.Fatal(r.err)
+		case tick := <-ticks:
+			tick <- time.Now()
+		}
+	}
+	tick := <-ticks
+	backend.Commit()
+	tick <- time.Now()
+	res := <-results
+	assert.Nil(res.err)
+	assert.Equal(types.ReceiptStatusSuccessful, res.receipt.Status)
+	assert.Equal(uint64(1), res.receipt.BlockNumber.Uint64())
+	mined, _, err := client.TransactionByHash(ctx, res.receipt.TxHash)
+	assert.Nil(err)
+	assert.Equal(uint64(0), mined.Nonce())
+	assert.Equal(big.NewInt(1250000000), mined.GasTipCap())
+	assert.Equal([]byte{1, 2, 3}, mined.Data())
+
+	keys, owners := testOwners(1)
+	tx = testTransfer(t, 1337)
+	sig, err := SignTx(hex.EncodeToString(tx.Marshal()), hex.EncodeToString(crypto.FromECDSA(keys[0])))
+	assert.Nil(err)
+	err = tx.AddOwnerSignature(owners, owners[0], common.FromHex(sig))
+	assert.Nil(err)
+	data, err := tx.BuildExecTransactionCalldata(owners, 1)
+	assert.Nil(err)
+	go func() {
+		receipt, err := relayer.Relay(ctx, tx, owners, 1)
+		results <- result{receipt, err}
+	}()
+	tick = <-ticks
+	backend.Commit()
+	tick <- time.Now()
+	res = <-results
+	assert.Nil(res.err)
+	assert.Equal(types.ReceiptStatusSuccessful, res.receipt.Status)
+	assert.Equal(uint64(2), res.receipt.BlockNumber.Uint64())
+	mined, _, err = client.TransactionByHash(ctx, res.receipt.TxHash)
+	assert.Nil(err)
+	assert.Equal(uint64(1), mined.Nonce())
+	assert.Equal(common.HexToAddress(tx.SafeAddress), *mined.To())
+	assert.Equal(data, mined.Data())
+
+	// the cached nonce is used by another transaction of the sender
+	chainID, err := client.ChainID(ctx)
+	assert.Nil(err)
+	external, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     2,
+		GasTipCap: big.NewInt(1000000000),
+		GasFeeCap: big.NewInt(10000000000),
+		Gas:       21000,
+		To:        &receiver,
+		Value:     big.NewInt(1),
+	}), types.LatestSignerForChainID(chainID), key)
+	assert.Nil(err)
+	err = client.SendTransaction(ctx, external)
+	assert.Nil(err)
+	backend.Commit()
+	go func() {
+		receipt, err := relayer.Send(ctx, receiver, []byte{4, 5, 6})
+		results <- result{receipt, err}
+	}()
+	tick = <-ticks
+	backend.Commit()
+	tick <- time.Now()
+	res = <-results
+	assert.Nil(res.err)
+	mined, _, err = client.TransactionByHash(ctx, res.receipt.TxHash)
+	assert.Nil(err)
+	assert.Equal(uint64(3), mined.Nonce())
+
+	low := &nonceTooLowBackend{Client: client}
+	relayer, err = NewRelayer(ctx, low, signer)
+	assert.Nil(err)
+	_, err = relayer.Send(ctx, receiver, []byte{4, 5, 6})
+	assert.ErrorContains(err, "nonce too low")
+	assert.Equal(4, low.sends)
+}
+
+// nonceTooLowBackend rejects all transactions as if the nonces were used
+type nonceTooLowBackend struct {
+	simulated.Client
+	sends int
+}
+
+func (b *nonceTooLowBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sends += 1
+	return fmt.Errorf("nonce too low: next nonce %d, tx nonce %d", tx.Nonce()+1, tx.Nonce())
+}
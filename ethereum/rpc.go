@@ -1,13 +1,11 @@
 package ethereum
 
 import (
-	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"math/big"
-	"net/http"
 	"strings"
 	"time"
 )
@@ -48,6 +46,17 @@ type RPCTransaction struct {
 	BlockHeight uint64
 }
 
+type RPCLog struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockHash       string   `json:"blockHash"`
+	BlockNumber     string   `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+	LogIndex        string   `json:"logIndex"`
+	Removed         bool     `json:"removed"`
+}
+
 type RPCBlockCallTrace struct {
 	Result *RPCTransactionCallTrace `json:"result"`
 }
@@ -64,8 +73,8 @@ type RPCTransactionCallTrace struct {
 	Value   string                     `json:"value"`
 }
 
-func RPCGetBlock(rpc, hash string) (*RPCBlock, error) {
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_getBlockByHash", []any{hash, false})
+func (c *RPCClient) GetBlock(ctx context.Context, hash string) (*RPCBlock, error) {
+	res, err := c.callUntilSufficient(ctx, "eth_getBlockByHash", []any{hash, false})
 	if err != nil {
 		return nil, err
 	}
@@ -87,8 +96,8 @@ func RPCGetBlock(rpc, hash string) (*RPCBlock, error) {
 	return &b, err
 }
 
-func RPCGetBlockHeight(rpc string) (int64, error) {
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_blockNumber", []any{})
+func (c *RPCClient) GetBlockHeight(ctx context.Context) (int64, error) {
+	res, err := c.callUntilSufficient(ctx, "eth_blockNumber", []any{})
 	if err != nil {
 		return 0, err
 	}
@@ -104,9 +113,9 @@ func RPCGetBlockHeight(rpc string) (int64, error) {
 	return int64(height), err
 }
 
-func RPCGetBlockHash(rpc string, height int64) (string, error) {
+func (c *RPCClient) GetBlockHash(ctx context.Context, height int64) (string, error) {
 	h := "0x" + hex.EncodeToString(new(big.Int).SetInt64(height).Bytes())
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_getBlockByNumber", []any{h})
+	res, err := c.callUntilSufficient(ctx, "eth_getBlockByNumber", []any{h})
 	if err != nil {
 		return "", err
 	}
@@ -118,8 +127,8 @@ func RPCGetBlockHash(rpc string, height int64) (string, error) {
 	return b.Hash, err
 }
 
-func RPCGetBlockWithTransactions(rpc, hash string) (*RPCBlockWithTransactions, error) {
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_getBlockByHash", []any{hash, true})
+func (c *RPCClient) GetBlockWithTransactions(ctx context.Context, hash string) (*RPCBlockWithTransactions, error) {
+	res, err := c.callUntilSufficient(ctx, "eth_getBlockByHash", []any{hash, true})
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +148,8 @@ func RPCGetBlockWithTransactions(rpc, hash string) (*RPCBlockWithTransactions, e
 	return &b, err
 }
 
-func RPCGetGasPrice(rpc string) (*big.Int, error) {
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_gasPrice", []any{})
+func (c *RPCClient) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	res, err := c.callUntilSufficient(ctx, "eth_gasPrice", []any{})
 	if err != nil {
 		return nil, err
 	}
@@ -159,12 +168,12 @@ func RPCGetGasPrice(rpc string) (*big.Int, error) {
 	return value, err
 }
 
-func RPCGetAddressBalance(rpc, txHash, address string) (*big.Int, error) {
-	tx, err := RPCGetTransactionByHash(rpc, txHash)
+func (c *RPCClient) GetAddressBalance(ctx context.Context, txHash, address string) (*big.Int, error) {
+	tx, err := c.GetTransactionByHash(ctx, txHash)
 	if err != nil {
 		return nil, err
 	}
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_getBalance", []any{address, tx.BlockHash})
+	res, err := c.callUntilSufficient(ctx, "eth_getBalance", []any{address, tx.BlockHash})
 	if err != nil {
 		return nil, err
 	}
@@ -180,8 +189,8 @@ func RPCGetAddressBalance(rpc, txHash, address string) (*big.Int, error) {
 	return balance, err
 }
 
-func RPCGetTransactionByHash(rpc, hash string) (*RPCTransaction, error) {
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_getTransactionByHash", []any{hash})
+func (c *RPCClient) GetTransactionByHash(ctx context.Context, hash string) (*RPCTransaction, error) {
+	res, err := c.callUntilSufficient(ctx, "eth_getTransactionByHash", []any{hash})
 	if err != nil {
 		return nil, err
 	}
@@ -198,11 +207,11 @@ func RPCGetTransactionByHash(rpc, hash string) (*RPCTransaction, error) {
 	return &b, err
 }
 
-func RPCDebugTraceTransactionByHash(rpc, hash string) (*RPCTransactionCallTrace, error) {
+func (c *RPCClient) DebugTraceTransactionByHash(ctx context.Context, hash string) (*RPCTransactionCallTrace, error) {
 	if !strings.HasPrefix(hash, "0x") {
 		hash = "0x" + hash
 	}
-	res, err := callEthereumRPCUntilSufficient(rpc, "debug_traceTransaction", []any{hash, map[string]any{"tracer": "callTracer"}})
+	res, err := c.callUntilSufficient(ctx, "debug_traceTransaction", []any{hash, map[string]any{"tracer": "callTracer"}})
 	if err != nil {
 		return nil, err
 	}
@@ -214,8 +223,8 @@ func RPCDebugTraceTransactionByHash(rpc, hash string) (*RPCTransactionCallTrace,
 	return &t, err
 }
 
-func RPCDebugTraceBlockByHash(rpc, hash string) ([]*RPCBlockCallTrace, error) {
-	res, err := callEthereumRPCUntilSufficient(rpc, "debug_traceBlockByHash", []any{hash, map[string]any{"tracer": "callTracer"}})
+func (c *RPCClient) DebugTraceBlockByHash(ctx context.Context, hash string) ([]*RPCBlockCallTrace, error) {
+	res, err := c.callUntilSufficient(ctx, "debug_traceBlockByHash", []any{hash, map[string]any{"tracer": "callTracer"}})
 	if err != nil {
 		return nil, err
 	}
@@ -227,8 +236,27 @@ func RPCDebugTraceBlockByHash(rpc, hash string) ([]*RPCBlockCallTrace, error) {
 	return txs, err
 }
 
-func RPCGetAddressBalanceAtBlock(rpc, blockHash, address string) (*big.Int, error) {
-	res, err := callEthereumRPCUntilSufficient(rpc, "eth_getBalance", []any{address, blockHash})
+// GetLogs returns the logs matching the eth_getLogs filter, e.g. with
+// blockHash or fromBlock and toBlock, and address and topics
+func (c *RPCClient) GetLogs(ctx context.Context, filter map[string]any) ([]*RPCLog, error) {
+	res, err := c.callUntilSufficient(ctx, "eth_getLogs", []any{filter})
+	if err != nil {
+		return nil, err
+	}
+	return decodeRPCLogs(res)
+}
+
+func decodeRPCLogs(res []byte) ([]*RPCLog, error) {
+	var logs []*RPCLog
+	err := json.Unmarshal(res, &logs)
+	if err != nil {
+		return nil, err
+	}
+	return logs, err
+}
+
+func (c *RPCClient) GetAddressBalanceAtBlock(ctx context.Context, blockHash, address string) (*big.Int, error) {
+	res, err := c.callUntilSufficient(ctx, "eth_getBalance", []any{address, blockHash})
 	if err != nil {
 		return nil, err
 	}
@@ -244,72 +272,115 @@ func RPCGetAddressBalanceAtBlock(rpc, blockHash, address string) (*big.Int, erro
 	return balance, err
 }
 
-func callEthereumRPCUntilSufficient(rpc, method string, params []any) ([]byte, error) {
-	for {
-		res, err := callEthereumRPC(rpc, method, params)
-		if err != nil {
-			reason := strings.ToLower(err.Error())
-			switch {
-			case strings.Contains(reason, "timeout"):
-			case strings.Contains(reason, "eof"):
-			case strings.Contains(reason, "handshake"):
-			default:
-				return res, err
-			}
-			time.Sleep(7 * time.Second)
-			continue
-		}
-		return res, err
+// Deprecated: use RPCClient.GetBlock
+func RPCGetBlock(rpc, hash string) (*RPCBlock, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
 	}
+	return c.GetBlock(context.Background(), hash)
 }
 
-func callEthereumRPC(rpc, method string, params []any) ([]byte, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
+// Deprecated: use RPCClient.GetBlockHeight
+func RPCGetBlockHeight(rpc string) (int64, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return 0, err
+	}
+	return c.GetBlockHeight(context.Background())
+}
 
-	body, err := json.Marshal(map[string]any{
-		"method":  method,
-		"params":  params,
-		"id":      time.Now().UnixNano(),
-		"jsonrpc": "2.0",
-	})
+// Deprecated: use RPCClient.GetBlockHash
+func RPCGetBlockHash(rpc string, height int64) (string, error) {
+	c, err := defaultRPCClient(rpc)
 	if err != nil {
-		panic(err)
+		return "", err
 	}
+	return c.GetBlockHash(context.Background(), height)
+}
 
-	req, err := http.NewRequest("POST", rpc, bytes.NewReader(body))
+// Deprecated: use RPCClient.GetBlockWithTransactions
+func RPCGetBlockWithTransactions(rpc, hash string) (*RPCBlockWithTransactions, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetBlockWithTransactions(context.Background(), hash)
+}
+
+// Deprecated: use RPCClient.GetGasPrice
+func RPCGetGasPrice(rpc string) (*big.Int, error) {
+	c, err := defaultRPCClient(rpc)
 	if err != nil {
-		return nil, buildRPCError(rpc, method, params, err)
+		return nil, err
 	}
+	return c.GetGasPrice(context.Background())
+}
 
-	req.Close = true
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(req)
+// Deprecated: use RPCClient.GetAddressBalance
+func RPCGetAddressBalance(rpc, txHash, address string) (*big.Int, error) {
+	c, err := defaultRPCClient(rpc)
 	if err != nil {
-		return nil, buildRPCError(rpc, method, params, err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return c.GetAddressBalance(context.Background(), txHash, address)
+}
 
-	body, err = io.ReadAll(resp.Body)
+// Deprecated: use RPCClient.GetTransactionByHash
+func RPCGetTransactionByHash(rpc, hash string) (*RPCTransaction, error) {
+	c, err := defaultRPCClient(rpc)
 	if err != nil {
-		return nil, buildRPCError(rpc, method, params, err)
+		return nil, err
 	}
-	var result struct {
-		Data  any `json:"result"`
-		Error any `json:"error"`
+	return c.GetTransactionByHash(context.Background(), hash)
+}
+
+// Deprecated: use RPCClient.DebugTraceTransactionByHash
+func RPCDebugTraceTransactionByHash(rpc, hash string) (*RPCTransactionCallTrace, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
 	}
-	err = json.Unmarshal(body, &result)
+	return c.DebugTraceTransactionByHash(context.Background(), hash)
+}
+
+// Deprecated: use RPCClient.DebugTraceBlockByHash
+func RPCDebugTraceBlockByHash(rpc, hash string) ([]*RPCBlockCallTrace, error) {
+	c, err := defaultRPCClient(rpc)
 	if err != nil {
-		return nil, fmt.Errorf("%v (%s)", buildRPCError(rpc, method, params, err), string(body))
+		return nil, err
 	}
-	if result.Error != nil {
-		return nil, fmt.Errorf("%v (%s)", buildRPCError(rpc, method, params, err), string(body))
+	return c.DebugTraceBlockByHash(context.Background(), hash)
+}
+
+// Deprecated: use RPCClient.GetLogs
+func RPCGetLogs(rpc string, filter map[string]any) ([]*RPCLog, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
 	}
+	return c.GetLogs(context.Background(), filter)
+}
 
-	return json.Marshal(result.Data)
+// Deprecated: use RPCClient.GetAddressBalanceAtBlock
+func RPCGetAddressBalanceAtBlock(rpc, blockHash, address string) (*big.Int, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetAddressBalanceAtBlock(context.Background(), blockHash, address)
 }
 
-func buildRPCError(rpc, method string, params []any, err error) error {
-	return fmt.Errorf("callEthereumRPC(%s, %s, %v) => %v", rpc, method, params, err)
+func isRetryableRPCError(err error) bool {
+	reason := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(reason, "timeout"):
+	case strings.Contains(reason, "eof"):
+	case strings.Contains(reason, "handshake"):
+	default:
+		return false
+	}
+	return true
 }
 
 func ethereumNumberToUint64(hex string) (uint64, error) {
@@ -0,0 +1,69 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthRPCClient(t *testing.T) {
+	assert := assert.New(t)
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal("secret", r.Header.Get("X-Api-Key"))
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		assert.Nil(err)
+		var result any
+		switch req["method"] {
+		case "eth_blockNumber":
+			result = "0x10"
+		case "eth_chainId":
+			result = "0x89"
+		case "eth_call":
+			result = "0x0000000000000000000000000000000000000000000000000000000000000002"
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req["id"], "result": result})
+	}))
+	defer server.Close()
+
+	rpc, err := NewRPCClient(RPCConfig{
+		Endpoint:  server.URL,
+		Headers:   map[string]string{"X-Api-Key": "secret"},
+		RateLimit: 20,
+		RateBurst: 1,
+	})
+	assert.Nil(err)
+	defer rpc.Close()
+
+	ctx := context.Background()
+	start := time.Now()
+	for range 5 {
+		height, err := rpc.GetBlockHeight(ctx)
+		assert.Nil(err)
+		assert.Equal(int64(16), height)
+	}
+	assert.GreaterOrEqual(time.Since(start), 190*time.Millisecond)
+
+	chainId, err := rpc.Eth().ChainID(ctx)
+	assert.Nil(err)
+	assert.Equal(int64(137), chainId.Int64())
+	threshold, err := rpc.GetThreshold(ctx, "0x5A2e2dA6b2A8B4C8f6A7bE1aB6d8E4cF1A0B2c3D")
+	assert.Nil(err)
+	assert.Equal(int64(2), threshold)
+	assert.Equal(int64(7), requests.Load())
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = rpc.GetBlockHeight(cancelled)
+	assert.ErrorIs(err, context.Canceled)
+	assert.Equal(int64(7), requests.Load())
+}
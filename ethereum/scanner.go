@@ -0,0 +1,337 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	scannerReorgDepth = 128
+
+	erc20TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+type Deposit struct {
+	*Transfer
+	BlockHash string
+	Height    uint64
+	// the deposit block is orphaned by a reorg
+	Removed bool
+}
+
+// ScannerCheckpoint is the next height to scan with the recent block hashes,
+// and it should be persisted after the deposits before it are credited
+type ScannerCheckpoint struct {
+	Height uint64            `json:"height"`
+	Hashes map[uint64]string `json:"hashes"`
+}
+
+// Scanner follows the blocks of an EVM chain, and emits native transfers,
+// including the internal ones in the call traces, and ERC20 Transfer logs to
+// the watched addresses. The rpc node should support debug_traceBlockByHash.
+// The deposits of orphaned blocks are emitted again as removed, except those
+// scanned before restart from a checkpoint.
+type Scanner struct {
+	rpc   ScannerRPC
+	chain byte
+
+	mutex     sync.RWMutex
+	addresses map[string]bool
+
+	// the scan state is locked during Scan, so Checkpoint never returns
+	// the state of a scan in progress or being restored
+	scanMutex sync.Mutex
+	height    uint64
+	hashes    map[uint64]string
+	deposits  map[string][]*Deposit
+
+	Confirmations uint64
+	PollInterval  time.Duration
+}
+
+// ScannerRPC is the chain reader of a Scanner, implemented by both the
+// RPCClient and the MultiRPCClient
+type ScannerRPC interface {
+	GetBlockHeight(ctx context.Context) (int64, error)
+	GetBlockHash(ctx context.Context, height int64) (string, error)
+	GetBlockWithTransactions(ctx context.Context, hash string) (*RPCBlockWithTransactions, error)
+	DebugTraceBlockByHash(ctx context.Context, hash string) ([]*RPCBlockCallTrace, error)
+	GetLogs(ctx context.Context, filter map[string]any) ([]*RPCLog, error)
+}
+
+func NewScanner(rpc ScannerRPC, chain byte, checkpoint *ScannerCheckpoint) *Scanner {
+	s := &Scanner{
+		rpc:          rpc,
+		chain:        chain,
+		addresses:    make(map[string]bool),
+		hashes:       make(map[uint64]string),
+		deposits:     make(map[string][]*Deposit),
+		PollInterval: 5 * time.Second,
+	}
+	if checkpoint == nil {
+		return s
+	}
+	s.height = checkpoint.Height
+	for h, hash := range checkpoint.Hashes {
+		s.hashes[h] = hash
+	}
+	return s
+}
+
+func (s *Scanner) Watch(addresses ...string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, a := range addresses {
+		norm := NormalizeAddress(a)
+		if norm == "" {
+			return fmt.Errorf("invalid address %s", a)
+		}
+		s.addresses[norm] = true
+	}
+	return nil
+}
+
+func (s *Scanner) Unwatch(addresses ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, a := range addresses {
+		delete(s.addresses, common.HexToAddress(a).Hex())
+	}
+}
+
+func (s *Scanner) watching(address common.Address) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.addresses[address.Hex()]
+}
+
+func (s *Scanner) Checkpoint() *ScannerCheckpoint {
+	s.scanMutex.Lock()
+	defer s.scanMutex.Unlock()
+
+	c := &ScannerCheckpoint{
+		Height: s.height,
+		Hashes: make(map[uint64]string, len(s.hashes)),
+	}
+	for h, hash := range s.hashes {
+		c.Hashes[h] = hash
+	}
+	return c
+}
+
+// Run scans until the context is done, and stops on the first error of
+// handler, which could be called again with the same deposit after restart
+func (s *Scanner) Run(ctx context.Context, handler func(*Deposit) error) error {
+	for {
+		deposits, err := s.Scan(ctx)
+		if err != nil {
+			return err
+		}
+		for _, d := range deposits {
+			err := handler(d)
+			if err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.PollInterval):
+		}
+	}
+}
+
+// Scan rolls back the orphaned blocks, then scans all blocks with enough
+// confirmations and returns the deposit events in order. The scanner is
+// restored on error, so the events and the checkpoint don't skip blocks.
+func (s *Scanner) Scan(ctx context.Context) ([]*Deposit, error) {
+	s.scanMutex.Lock()
+	defer s.scanMutex.Unlock()
+
+	height, hashes, deposits := s.height, maps.Clone(s.hashes), maps.Clone(s.deposits)
+	events, err := s.scan(ctx)
+	if err != nil {
+		s.height, s.hashes, s.deposits = height, hashes, deposits
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Scanner) scan(ctx context.Context) ([]*Deposit, error) {
+	events, err := s.rollback(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := s.rpc.GetBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for ; int64(s.height+s.Confirmations) <= tip; s.height++ {
+		hash, err := s.rpc.GetBlockHash(ctx, int64(s.height))
+		if err != nil {
+			return nil, err
+		}
+		deposits, err := s.scanBlock(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, deposits...)
+		s.hashes[s.height] = hash
+		s.deposits[hash] = deposits
+		if old, found := s.hashes[s.height-scannerReorgDepth]; found {
+			delete(s.hashes, s.height-scannerReorgDepth)
+			delete(s.deposits, old)
+		}
+	}
+	return events, nil
+}
+
+// rollback walks back from the last scanned block until the hash matches
+// the chain, and emits the removed deposits of the orphaned blocks
+func (s *Scanner) rollback(ctx context.Context) ([]*Deposit, error) {
+	var events []*Deposit
+	for s.height > 0 {
+		known, found := s.hashes[s.height-1]
+		if !found {
+			break
+		}
+		hash, err := s.rpc.GetBlockHash(ctx, int64(s.height-1))
+		if err != nil {
+			return nil, err
+		}
+		if hash == known {
+			break
+		}
+		s.height = s.height - 1
+		delete(s.hashes, s.height)
+		for _, d := range s.deposits[known] {
+			e := *d
+			e.Removed = true
+			events = append(events, &e)
+		}
+		delete(s.deposits, known)
+	}
+	return events, nil
+}
+
+func (s *Scanner) scanBlock(ctx context.Context, hash string) ([]*Deposit, error) {
+	block, err := s.rpc.GetBlockWithTransactions(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block.Height != s.height {
+		return nil, fmt.Errorf("block %s height %d %d", hash, block.Height, s.height)
+	}
+	traces, err := s.rpc.DebugTraceBlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(traces) != len(block.Tx) {
+		return nil, fmt.Errorf("block %s traces %d %d", hash, len(traces), len(block.Tx))
+	}
+
+	var deposits []*Deposit
+	for i, t := range traces {
+		var index int64
+		for _, transfer := range s.traceTransfers(block.Tx[i].Hash, t.Result, &index) {
+			deposits = append(deposits, &Deposit{Transfer: transfer, BlockHash: hash, Height: block.Height})
+		}
+	}
+
+	logs, err := s.rpc.GetLogs(ctx, map[string]any{
+		"blockHash": hash,
+		"topics":    []any{erc20TransferTopic},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		transfer, err := s.logTransfer(l)
+		if err != nil {
+			return nil, err
+		}
+		if transfer != nil {
+			deposits = append(deposits, &Deposit{Transfer: transfer, BlockHash: hash, Height: block.Height})
+		}
+	}
+	return deposits, nil
+}
+
+// traceTransfers indexes the call frames in depth first order, and skips the
+// reverted frames and all their children
+func (s *Scanner) traceTransfers(hash string, call *RPCTransactionCallTrace, index *int64) []*Transfer {
+	if call == nil {
+		return nil
+	}
+	i := *index
+	*index = *index + 1
+	if call.Error != "" {
+		skipCallTraces(call.Calls, index)
+		return nil
+	}
+
+	var transfers []*Transfer
+	value, _ := new(big.Int).SetString(call.Value, 0)
+	switch strings.ToUpper(call.Type) {
+	case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+		to := common.HexToAddress(call.To)
+		if value != nil && value.Sign() > 0 && s.watching(to) {
+			transfers = append(transfers, &Transfer{
+				Hash:         hash,
+				Index:        i,
+				TokenAddress: EthereumEmptyAddress,
+				AssetId:      GetMixinChainID(int64(s.chain)),
+				Sender:       common.HexToAddress(call.From).Hex(),
+				Receiver:     to.Hex(),
+				Value:        value,
+			})
+		}
+	}
+	for _, c := range call.Calls {
+		transfers = append(transfers, s.traceTransfers(hash, c, index)...)
+	}
+	return transfers
+}
+
+func skipCallTraces(calls []*RPCTransactionCallTrace, index *int64) {
+	for _, c := range calls {
+		*index = *index + 1
+		skipCallTraces(c.Calls, index)
+	}
+}
+
+// logTransfer ignores ERC721 Transfer, which has the token id as the last topic
+func (s *Scanner) logTransfer(l *RPCLog) (*Transfer, error) {
+	if l.Removed || len(l.Topics) != 3 || l.Topics[0] != erc20TransferTopic {
+		return nil, nil
+	}
+	to := common.HexToAddress(l.Topics[2])
+	if !s.watching(to) {
+		return nil, nil
+	}
+	data := common.FromHex(l.Data)
+	if len(data) != 32 {
+		return nil, nil
+	}
+	index, err := ethereumNumberToUint64(l.LogIndex)
+	if err != nil {
+		return nil, err
+	}
+	token := common.HexToAddress(l.Address)
+	return &Transfer{
+		Hash:         l.TransactionHash,
+		Index:        int64(index),
+		TokenAddress: token.Hex(),
+		AssetId:      GenerateAssetId(s.chain, strings.ToLower(token.Hex())),
+		Sender:       common.HexToAddress(l.Topics[1]).Hex(),
+		Receiver:     to.Hex(),
+		Value:        new(big.Int).SetBytes(data),
+	}, nil
+}
@@ -0,0 +1,170 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthScanner(t *testing.T) {
+	assert := assert.New(t)
+
+	safe := testSafeAddress
+	sender := "0x9d04735aaEB73535672200950fA77C2dFC86eB21"
+	token := "0xc2132D05D31c914a87C6611C10748AEb04B58e8F"
+	topic := func(addr string) string {
+		return hex.EncodeToString(common.LeftPadBytes(common.HexToAddress(addr).Bytes(), 32))
+	}
+	type block struct {
+		hash   string
+		txs    []string
+		traces []map[string]any
+		logs   []map[string]any
+	}
+	var mutex sync.Mutex
+	var failing string
+	chain := []*block{{hash: "0xb0"}, {
+		hash: "0xb1",
+		txs:  []string{"0xa1", "0xa2"},
+		traces: []map[string]any{
+			{"result": map[string]any{"type": "CALL", "from": sender, "to": safe, "value": "0x10"}},
+			{"result": map[string]any{"type": "CALL", "from": sender, "to": "0x1111111111111111111111111111111111111111", "value": "0x0", "calls": []any{
+				map[string]any{"type": "CALL", "from": sender, "to": safe, "value": "0x20", "error": "execution reverted", "calls": []any{
+					map[string]any{"type": "CALL", "from": sender, "to": safe, "value": "0x30"},
+				}},
+				map[string]any{"type": "DELEGATECALL", "from": sender, "to": safe, "value": "0x40"},
+				map[string]any{"type": "CALL", "from": sender, "to": safe, "value": "0x50"},
+			}}},
+		},
+		logs: []map[string]any{
+			{"address": token, "transactionHash": "0xa2", "logIndex": "0x3", "topics": []any{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", "0x" + topic(sender), "0x" + topic(safe)}, "data": "0x" + hex.EncodeToString(common.LeftPadBytes(big.NewInt(1000).Bytes(), 32))},
+			{"address": token, "transactionHash": "0xa2", "logIndex": "0x4", "topics": []any{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", "0x" + topic(sender), "0x" + topic(safe), "0x01"}, "data": "0x"},
+		},
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		params := req["params"].([]any)
+		find := func(hash any) (int, *block) {
+			for i, b := range chain {
+				if b.hash == hash {
+					return i, b
+				}
+			}
+			return 0, nil
+		}
+		var result any
+		switch req["method"] {
+		case "eth_blockNumber":
+			result = fmt.Sprintf("0x%x", len(chain)-1)
+		case "eth_getBlockByNumber":
+			n, _ := new(big.Int).SetString(params[0].(string), 0)
+			result = map[string]any{"hash": chain[n.Int64()].hash}
+		case "eth_getBlockByHash":
+			i, b := find(params[0])
+			var txs []any
+			for _, h := range b.txs {
+				txs = append(txs, map[string]any{"hash": h})
+			}
+			result = map[string]any{"hash": b.hash, "number": fmt.Sprintf("0x%x", i), "transactions": txs}
+		case "debug_traceBlockByHash":
+			if params[0] == failing {
+				json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req["id"], "error": map[string]any{"code": -32000, "message": "header not found"}})
+				return
+			}
+			_, b := find(params[0])
+			result = b.traces
+		case "eth_getLogs":
+			_, b := find(params[0].(map[string]any)["blockHash"])
+			result = b.logs
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req["id"], "result": result})
+	}))
+	defer server.Close()
+
+	rpc, err := NewRPCClient(RPCConfig{Endpoint: server.URL})
+	assert.Nil(err)
+	defer rpc.Close()
+	ctx := context.Background()
+	scanner := NewScanner(rpc, ChainPolygon, &ScannerCheckpoint{Height: 1})
+	err = scanner.Watch(safe)
+	assert.Nil(err)
+	deposits, err := scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 3)
+	assert.Equal("0xa1", deposits[0].Hash)
+	assert.Equal(EthereumEmptyAddress, deposits[0].TokenAddress)
+	assert.Equal(GetMixinChainID(ChainPolygon), deposits[0].AssetId)
+	assert.Equal(int64(16), deposits[0].Value.Int64())
+	assert.Equal("0xa2", deposits[1].Hash)
+	assert.Equal(int64(4), deposits[1].Index)
+	assert.Equal(int64(80), deposits[1].Value.Int64())
+	assert.Equal(sender, deposits[1].Sender)
+	assert.Equal(token, deposits[2].TokenAddress)
+	assert.Equal(int64(3), deposits[2].Index)
+	assert.Equal(int64(1000), deposits[2].Value.Int64())
+	assert.Equal(uint64(1), deposits[2].Height)
+
+	checkpoint := scanner.Checkpoint()
+	assert.Equal(uint64(2), checkpoint.Height)
+	assert.Equal("0xb1", checkpoint.Hashes[1])
+	data, err := json.Marshal(checkpoint)
+	assert.Nil(err)
+	var restored ScannerCheckpoint
+	err = json.Unmarshal(data, &restored)
+	assert.Nil(err)
+	assert.Equal(checkpoint, &restored)
+
+	mutex.Lock()
+	deposited := chain[1]
+	chain[1] = &block{hash: "0xb1b1"}
+	chain = append(chain, &block{hash: "0xb2"})
+	mutex.Unlock()
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 3)
+	for _, d := range deposits {
+		assert.True(d.Removed)
+	}
+	assert.Equal(uint64(3), scanner.Checkpoint().Height)
+
+	scanner = NewScanner(rpc, ChainPolygon, &restored)
+	scanner.Confirmations = 1
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 0)
+	assert.Equal(uint64(2), scanner.Checkpoint().Height)
+	assert.Equal("0xb1b1", scanner.Checkpoint().Hashes[1])
+
+	// the deposits of block 1 are not lost when block 2 fails
+	mutex.Lock()
+	chain[1] = deposited
+	failing = "0xb2"
+	mutex.Unlock()
+	scanner = NewScanner(rpc, ChainPolygon, &ScannerCheckpoint{Height: 1})
+	err = scanner.Watch(safe)
+	assert.Nil(err)
+	_, err = scanner.Scan(ctx)
+	assert.ErrorContains(err, "header not found")
+	assert.Equal(uint64(1), scanner.Checkpoint().Height)
+	assert.Len(scanner.Checkpoint().Hashes, 0)
+	mutex.Lock()
+	failing = ""
+	mutex.Unlock()
+	deposits, err = scanner.Scan(ctx)
+	assert.Nil(err)
+	assert.Len(deposits, 3)
+	assert.Equal("0xb1", deposits[0].BlockHash)
+	assert.Equal(uint64(3), scanner.Checkpoint().Height)
+}
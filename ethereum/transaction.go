@@ -1,11 +1,13 @@
 package ethereum
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/MixinNetwork/go-safe-sdk/bitcoin"
@@ -39,16 +41,23 @@ type SafeTransaction struct {
 	Signatures     [][]byte
 }
 
+// Output with TokenStandard transfers NFTs, the ERC721 output has a single
+// token id, and the ERC1155 output has TokenAmounts for each of TokenIds
 type Output struct {
-	TokenAddress string
-	Destination  string
-	Amount       *big.Int
+	TokenAddress  string
+	Destination   string
+	Amount        *big.Int
+	TokenStandard int
+	TokenIds      []*big.Int
+	TokenAmounts  []*big.Int
 }
 
 func CreateTransactionFromOutputs(ctx context.Context, typ int, chainId int64, id, safeAddress string, outputs []*Output, nonce *big.Int) (*SafeTransaction, error) {
 	switch {
 	case len(outputs) > 1 && typ == TypeMultiSendTx:
 		return CreateMultiSendTransaction(ctx, chainId, id, safeAddress, outputs, nonce)
+	case len(outputs) == 1 && outputs[0].TokenStandard != 0:
+		return CreateNFTTransaction(ctx, chainId, id, safeAddress, outputs[0], nonce)
 	case len(outputs) == 1:
 		o := outputs[0]
 		return CreateTransaction(ctx, typ, chainId, id, safeAddress, o.Destination, o.TokenAddress, o.Amount.String(), nonce)
@@ -77,7 +86,6 @@ func CreateTransaction(ctx context.Context, typ int, chainID int64, id, safeAddr
 		GasToken:       common.HexToAddress(EthereumEmptyAddress),
 		RefundReceiver: common.HexToAddress(EthereumEmptyAddress),
 		Nonce:          nonce,
-		Signatures:     make([][]byte, 3),
 	}
 	switch typ {
 	case TypeETHTx:
@@ -89,6 +97,14 @@ func CreateTransaction(ctx context.Context, typ int, chainID int64, id, safeAddr
 		tx.Destination = common.HexToAddress(norm)
 		tx.Value = big.NewInt(0)
 		tx.Data = GetERC20TxData(destination, value)
+	case TypeERC20ApproveTx:
+		norm := NormalizeAddress(tokenAddress)
+		if norm == "" {
+			return nil, fmt.Errorf("invalid ERC20 address %s for TypeERC20ApproveTx", tokenAddress)
+		}
+		tx.Destination = common.HexToAddress(norm)
+		tx.Value = big.NewInt(0)
+		tx.Data = GetERC20ApproveData(destination, value)
 	default:
 		return nil, fmt.Errorf("invalid safe transaction type: %d", typ)
 	}
@@ -101,12 +117,16 @@ func CreateMultiSendTransaction(ctx context.Context, chainID int64, id, safeAddr
 	if nonce == nil {
 		return nil, fmt.Errorf("Invalid ethereum transaction nonce")
 	}
+	data, err := GetSafeMultiSendData(safeAddress, outputs)
+	if err != nil {
+		return nil, err
+	}
 	tx := &SafeTransaction{
 		ChainID:        chainID,
 		SafeAddress:    safeAddress,
-		Destination:    common.HexToAddress(EthereumMultiSendAddress),
+		Destination:    common.HexToAddress(GetSafeDeployment(chainID).MultiSend),
 		Value:          big.NewInt(0),
-		Data:           GetMultiSendData(outputs),
+		Data:           data,
 		Operation:      operationTypeDelegateCall,
 		SafeTxGas:      big.NewInt(0),
 		BaseGas:        big.NewInt(0),
@@ -114,7 +134,6 @@ func CreateMultiSendTransaction(ctx context.Context, chainID int64, id, safeAddr
 		GasToken:       common.HexToAddress(EthereumEmptyAddress),
 		RefundReceiver: common.HexToAddress(EthereumEmptyAddress),
 		Nonce:          nonce,
-		Signatures:     make([][]byte, 3),
 	}
 	tx.Message = tx.GetTransactionHash()
 	tx.TxHash = tx.Hash(id)
@@ -129,7 +148,7 @@ func CreateEnableGuardTransaction(ctx context.Context, chainID int64, id, safeAd
 	tx := &SafeTransaction{
 		ChainID:        chainID,
 		SafeAddress:    safeAddress,
-		Destination:    common.HexToAddress(EthereumMultiSendAddress),
+		Destination:    common.HexToAddress(GetSafeDeployment(chainID).MultiSend),
 		Value:          zero,
 		Operation:      operationTypeDelegateCall,
 		SafeTxGas:      zero,
@@ -138,9 +157,59 @@ func CreateEnableGuardTransaction(ctx context.Context, chainID int64, id, safeAd
 		GasToken:       common.HexToAddress(EthereumEmptyAddress),
 		RefundReceiver: common.HexToAddress(EthereumEmptyAddress),
 		Nonce:          zero,
-		Signatures:     make([][]byte, 3),
 	}
-	tx.Data = tx.GetEnableGuradData(observerAddress, timelock)
+	data, err := tx.GetEnableGuradData(observerAddress, timelock)
+	if err != nil {
+		return nil, err
+	}
+	tx.Data = data
+	tx.Message = tx.GetTransactionHash()
+	tx.TxHash = tx.Hash(id)
+	return tx, nil
+}
+
+// CreateERC20ApproveTransaction approves the spender with the amount, and if
+// the current allowance is not zero, it's reset to zero first in a MultiSend
+// for tokens rejecting the approval from non-zero to non-zero
+func CreateERC20ApproveTransaction(ctx context.Context, chainID int64, id, safeAddress, tokenAddress, spender string, amount, allowance *big.Int, nonce *big.Int) (*SafeTransaction, error) {
+	if amount == nil || amount.Sign() < 0 || allowance == nil {
+		return nil, fmt.Errorf("invalid ERC20 approve amount %v allowance %v", amount, allowance)
+	}
+	if allowance.Sign() == 0 || amount.Sign() == 0 {
+		return CreateTransaction(ctx, TypeERC20ApproveTx, chainID, id, safeAddress, spender, tokenAddress, amount.String(), nonce)
+	}
+	if nonce == nil {
+		return nil, fmt.Errorf("Invalid ethereum transaction nonce")
+	}
+	norm := NormalizeAddress(tokenAddress)
+	if norm == "" {
+		return nil, fmt.Errorf("invalid ERC20 address %s for TypeERC20ApproveTx", tokenAddress)
+	}
+	token := common.HexToAddress(norm)
+	data := GetMetaTxData(token, big.NewInt(0), GetERC20ApproveData(spender, big.NewInt(0)))
+	data = append(data, GetMetaTxData(token, big.NewInt(0), GetERC20ApproveData(spender, amount))...)
+	multiSend, err := ga.JSON(strings.NewReader(abi.MultiSendMetaData.ABI))
+	if err != nil {
+		panic(err)
+	}
+	args, err := multiSend.Pack("multiSend", data)
+	if err != nil {
+		panic(err)
+	}
+	tx := &SafeTransaction{
+		ChainID:        chainID,
+		SafeAddress:    safeAddress,
+		Destination:    common.HexToAddress(GetSafeDeployment(chainID).MultiSend),
+		Value:          big.NewInt(0),
+		Data:           args,
+		Operation:      operationTypeDelegateCall,
+		SafeTxGas:      big.NewInt(0),
+		BaseGas:        big.NewInt(0),
+		GasPrice:       big.NewInt(0),
+		GasToken:       common.HexToAddress(EthereumEmptyAddress),
+		RefundReceiver: common.HexToAddress(EthereumEmptyAddress),
+		Nonce:          nonce,
+	}
 	tx.Message = tx.GetTransactionHash()
 	tx.TxHash = tx.Hash(id)
 	return tx, nil
@@ -154,28 +223,103 @@ func (tx *SafeTransaction) Hash(id string) string {
 	return hex.EncodeToString(hash)
 }
 
+// the legacy encoding starts with the uint64 chain id, so the magic would
+// never be a valid prefix of it
+var safeTransactionMagic = []byte{0xff, 0xff, 'S', 'T'}
+
+const safeTransactionVersion = 1
+
 func (tx *SafeTransaction) Marshal() []byte {
 	enc := mc.NewEncoder()
+	enc.Write(safeTransactionMagic)
+	_ = enc.WriteByte(safeTransactionVersion)
 	enc.WriteUint64(uint64(tx.ChainID))
-	enc.WriteUint64(uint64(tx.Operation))
+	_ = enc.WriteByte(tx.Operation)
 	bitcoin.WriteBytes(enc, []byte(tx.TxHash))
 	bitcoin.WriteBytes(enc, []byte(tx.SafeAddress))
 	bitcoin.WriteBytes(enc, tx.Destination.Bytes())
-	bitcoin.WriteBytes(enc, tx.Value.Bytes())
+	bitcoin.WriteBytes(enc, bigBytes(tx.Value))
 	bitcoin.WriteBytes(enc, tx.Data)
-	bitcoin.WriteBytes(enc, tx.Nonce.Bytes())
+	bitcoin.WriteBytes(enc, bigBytes(tx.SafeTxGas))
+	bitcoin.WriteBytes(enc, bigBytes(tx.BaseGas))
+	bitcoin.WriteBytes(enc, bigBytes(tx.GasPrice))
+	bitcoin.WriteBytes(enc, tx.GasToken.Bytes())
+	bitcoin.WriteBytes(enc, tx.RefundReceiver.Bytes())
+	bitcoin.WriteBytes(enc, bigBytes(tx.Nonce))
 	bitcoin.WriteBytes(enc, tx.Message)
 
-	var signatures []string
+	enc.WriteInt(len(tx.Signatures))
 	for _, sig := range tx.Signatures {
-		signatures = append(signatures, hex.EncodeToString(sig))
+		bitcoin.WriteBytes(enc, sig)
 	}
-	sigs := strings.Join(signatures, ",")
-	bitcoin.WriteBytes(enc, []byte(sigs))
 	return enc.Bytes()
 }
 
 func UnmarshalSafeTransaction(b []byte) (*SafeTransaction, error) {
+	if !bytes.HasPrefix(b, safeTransactionMagic) {
+		return unmarshalLegacySafeTransaction(b)
+	}
+	dec := mc.NewDecoder(b[len(safeTransactionMagic):])
+	version, err := dec.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version != safeTransactionVersion {
+		return nil, fmt.Errorf("invalid safe transaction version %d", version)
+	}
+	chainID, err := dec.ReadUint64()
+	if err != nil {
+		return nil, err
+	}
+	operation, err := dec.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	tx := &SafeTransaction{
+		ChainID:   int64(chainID),
+		Operation: operation,
+	}
+
+	var fields [12][]byte
+	for i := range fields {
+		fields[i], err = dec.ReadBytes()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range [][]byte{fields[2], fields[8], fields[9]} {
+		if len(a) != common.AddressLength {
+			return nil, fmt.Errorf("invalid safe transaction address %x", a)
+		}
+	}
+	tx.TxHash = string(fields[0])
+	tx.SafeAddress = string(fields[1])
+	tx.Destination = common.BytesToAddress(fields[2])
+	tx.Value = new(big.Int).SetBytes(fields[3])
+	tx.Data = fields[4]
+	tx.SafeTxGas = new(big.Int).SetBytes(fields[5])
+	tx.BaseGas = new(big.Int).SetBytes(fields[6])
+	tx.GasPrice = new(big.Int).SetBytes(fields[7])
+	tx.GasToken = common.BytesToAddress(fields[8])
+	tx.RefundReceiver = common.BytesToAddress(fields[9])
+	tx.Nonce = new(big.Int).SetBytes(fields[10])
+	tx.Message = fields[11]
+
+	count, err := dec.ReadInt()
+	if err != nil {
+		return nil, err
+	}
+	tx.Signatures = make([][]byte, count)
+	for i := range tx.Signatures {
+		tx.Signatures[i], err = dec.ReadBytes()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+func unmarshalLegacySafeTransaction(b []byte) (*SafeTransaction, error) {
 	dec := mc.NewDecoder(b)
 	chainID, err := dec.ReadUint64()
 	if err != nil {
@@ -219,7 +363,7 @@ func UnmarshalSafeTransaction(b []byte) (*SafeTransaction, error) {
 	}
 	sigsStr := strings.Split(string(signature), ",")
 
-	signatures := make([][]byte, 3)
+	signatures := make([][]byte, max(3, len(sigsStr)))
 	for i, s := range sigsStr {
 		if s == "" {
 			continue
@@ -250,30 +394,38 @@ func UnmarshalSafeTransaction(b []byte) (*SafeTransaction, error) {
 	}, nil
 }
 
-func (tx *SafeTransaction) ExtractOutputs() []*Output {
-	outputs, err := tx.ParseMultiSendData()
-	if err == nil {
-		return outputs
+// ExtractOutputs returns the transfers of the transaction, and an error for
+// any calldata not of a transfer, use DecodeActions for the other calls
+func (tx *SafeTransaction) ExtractOutputs() ([]*Output, error) {
+	if tx.Operation == operationTypeDelegateCall {
+		return tx.ParseMultiSendData()
 	}
 	switch {
 	case len(tx.Data) == 0:
 		return []*Output{{
 			Destination: tx.Destination.Hex(),
 			Amount:      tx.Value,
-		}}
-	default:
-		method := hex.EncodeToString(tx.Data[0:4])
-		if method != "a9059cbb" || len(tx.Data) != 68 {
-			panic("invalid safe transaction data")
-		}
-		destination := tx.Data[4:36]
-		value := tx.Data[36:68]
-		return []*Output{{
-			TokenAddress: tx.Destination.Hex(),
-			Destination:  common.BytesToAddress(destination).Hex(),
-			Amount:       new(big.Int).SetBytes(value),
-		}}
-	}
+		}}, nil
+	case len(tx.Data) < 4:
+		return nil, fmt.Errorf("invalid safe transaction data %x", tx.Data)
+	}
+	method := hex.EncodeToString(tx.Data[0:4])
+	if method == "095ea7b3" && len(tx.Data) == 68 {
+		return nil, fmt.Errorf("erc20 approve is not a transfer, use DecodeActions: %x", tx.Data)
+	}
+	if o := parseNFTOutput(common.HexToAddress(tx.SafeAddress), tx.Destination, tx.Data); o != nil {
+		return []*Output{o}, nil
+	}
+	if method != "a9059cbb" || len(tx.Data) != 68 {
+		return nil, fmt.Errorf("invalid safe transaction data %x", tx.Data)
+	}
+	destination := tx.Data[4:36]
+	value := tx.Data[36:68]
+	return []*Output{{
+		TokenAddress: tx.Destination.Hex(),
+		Destination:  common.BytesToAddress(destination).Hex(),
+		Amount:       new(big.Int).SetBytes(value),
+	}}, nil
 }
 
 func (tx *SafeTransaction) GetTransactionHash() []byte {
@@ -296,11 +448,13 @@ func (tx *SafeTransaction) ParseMultiSendData() ([]*Output, error) {
 	if err != nil {
 		panic(err)
 	}
-	args, err := abi.Methods["multiSend"].Inputs.Unpack(
-		tx.Data[4:],
-	)
+	method := abi.Methods["multiSend"]
+	if len(tx.Data) < 4 || !bytes.Equal(tx.Data[:4], method.ID) {
+		return nil, fmt.Errorf("invalid multiSend data %x", tx.Data)
+	}
+	args, err := method.Inputs.Unpack(tx.Data[4:])
 	if err != nil || len(args) != 1 {
-		return nil, err
+		return nil, fmt.Errorf("invalid multiSend data %x", tx.Data)
 	}
 	multiSendData := args[0].([]byte)
 
@@ -310,7 +464,13 @@ func (tx *SafeTransaction) ParseMultiSendData() ([]*Output, error) {
 		if offset == len(multiSendData) {
 			break
 		}
+		if len(multiSendData)-offset < 85 {
+			return nil, fmt.Errorf("invalid multiSend transaction at %d", offset)
+		}
 
+		if op := multiSendData[offset]; op != operationTypeCall {
+			return nil, fmt.Errorf("invalid multiSend operation %d at %d", op, offset)
+		}
 		offset += 1
 		bytesTo := multiSendData[offset : offset+20]
 		to := common.BytesToAddress(bytesTo)
@@ -321,6 +481,9 @@ func (tx *SafeTransaction) ParseMultiSendData() ([]*Output, error) {
 		bytesLen := multiSendData[offset : offset+32]
 		dataLen := new(big.Int).SetBytes(bytesLen).Uint64()
 		offset += 32
+		if dataLen > uint64(len(multiSendData)-offset) {
+			return nil, fmt.Errorf("invalid multiSend transaction data size %d", dataLen)
+		}
 
 		o := &Output{
 			Destination: to.Hex(),
@@ -343,10 +506,18 @@ func (tx *SafeTransaction) ParseMultiSendData() ([]*Output, error) {
 				o.Destination = common.BytesToAddress(bytesTo).Hex()
 				o.Amount = new(big.Int).SetBytes(bytesAmount)
 				offset += int(dataLen)
+			case "095ea7b3": // erc20 approve
+				return nil, fmt.Errorf("erc20 approve is not a transfer, use DecodeActions: %x", metaData)
 			default:
 				return nil, fmt.Errorf("invalid meta tx data: %x", metaData)
 			}
 		default:
+			metaData := multiSendData[offset : offset+int(dataLen)]
+			nft := parseNFTOutput(common.HexToAddress(tx.SafeAddress), to, metaData)
+			if nft == nil {
+				return nil, fmt.Errorf("invalid meta tx data: %x", metaData)
+			}
+			o = nft
 			offset += int(dataLen)
 		}
 		os = append(os, o)
@@ -354,14 +525,19 @@ func (tx *SafeTransaction) ParseMultiSendData() ([]*Output, error) {
 	return os, nil
 }
 
-func (tx *SafeTransaction) GetEnableGuradData(observer string, timelock *big.Int) []byte {
+func (tx *SafeTransaction) GetEnableGuradData(observer string, timelock *big.Int) ([]byte, error) {
+	d := GetSafeDeployment(tx.ChainID)
+	if d.SafeGuard == "" {
+		return nil, fmt.Errorf("no safe guard for evm chain id %d", tx.ChainID)
+	}
+	guard := common.HexToAddress(d.SafeGuard)
 	safeAbi, err := ga.JSON(strings.NewReader(abi.GnosisSafeMetaData.ABI))
 	if err != nil {
 		panic(err)
 	}
 	args, err := safeAbi.Pack(
 		"setGuard",
-		common.HexToAddress(EthereumSafeGuardAddress),
+		guard,
 	)
 	if err != nil {
 		panic(err)
@@ -380,7 +556,7 @@ func (tx *SafeTransaction) GetEnableGuradData(observer string, timelock *big.Int
 	if err != nil {
 		panic(err)
 	}
-	guardSafeData := GetMetaTxData(common.HexToAddress(EthereumSafeGuardAddress), big.NewInt(0), args)
+	guardSafeData := GetMetaTxData(guard, big.NewInt(0), args)
 
 	data := []byte{}
 	data = append(data, setGuardData...)
@@ -396,7 +572,7 @@ func (tx *SafeTransaction) GetEnableGuradData(observer string, timelock *big.Int
 	if err != nil {
 		panic(err)
 	}
-	return args
+	return args, nil
 }
 
 func GetERC20TxData(receiver string, amount *big.Int) []byte {
@@ -415,12 +591,42 @@ func GetERC20TxData(receiver string, amount *big.Int) []byte {
 	return data
 }
 
+func GetERC20ApproveData(spender string, amount *big.Int) []byte {
+	assetAbi, err := ga.JSON(strings.NewReader(abi.AssetMetaData.ABI))
+	if err != nil {
+		panic(err)
+	}
+	args, err := assetAbi.Pack("approve", common.HexToAddress(spender), amount)
+	if err != nil {
+		panic(err)
+	}
+	return args
+}
+
 func GetMultiSendData(outputs []*Output) []byte {
+	args, err := GetSafeMultiSendData("", outputs)
+	if err != nil {
+		panic(err)
+	}
+	return args
+}
+
+// GetSafeMultiSendData requires the safe address as the sender of NFTs
+func GetSafeMultiSendData(safeAddress string, outputs []*Output) ([]byte, error) {
 	metaTxsData := []byte{}
 	for _, o := range outputs {
 		destination, amount, data := o.Destination, o.Amount, []byte{}
 		norm := NormalizeAddress(o.TokenAddress)
-		if norm != "" {
+		if o.TokenStandard != 0 {
+			if NormalizeAddress(safeAddress) == "" {
+				return nil, fmt.Errorf("invalid safe address %s for NFT", safeAddress)
+			}
+			nft, err := GetNFTTxData(safeAddress, o)
+			if err != nil {
+				return nil, err
+			}
+			destination, amount, data = norm, big.NewInt(0), nft
+		} else if norm != "" {
 			destination = norm
 			amount = big.NewInt(0)
 			data = GetERC20TxData(o.Destination, o.Amount)
@@ -440,7 +646,7 @@ func GetMultiSendData(outputs []*Output) []byte {
 	if err != nil {
 		panic(err)
 	}
-	return args
+	return args, nil
 }
 
 func GetMetaTxData(to common.Address, amount *big.Int, data []byte) []byte {
@@ -523,7 +729,16 @@ func CheckTransactionSignature(raw, public string, signature []byte) (bool, erro
 	return true, nil
 }
 
+// Deprecated: use RPCClient.AddSignature
 func AddSignature(rpc, raw, public string, sig []byte) (string, error) {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return "", err
+	}
+	return c.AddSignature(context.Background(), raw, public, sig)
+}
+
+func (c *RPCClient) AddSignature(ctx context.Context, raw, public string, sig []byte) (string, error) {
 	b, err := hex.DecodeString(raw)
 	if err != nil {
 		return "", err
@@ -532,7 +747,7 @@ func AddSignature(rpc, raw, public string, sig []byte) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	os, err := GetOwners(rpc, st.SafeAddress)
+	os, err := c.GetOwners(ctx, st.SafeAddress)
 	if err != nil {
 		return "", err
 	}
@@ -540,11 +755,111 @@ func AddSignature(rpc, raw, public string, sig []byte) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	for i, o := range os {
-		if o.Cmp(*addr) == 0 {
-			st.Signatures[i] = sig
-		}
+	err = st.AddOwnerSignature(os, *addr, sig)
+	if err != nil {
+		return "", err
 	}
 	raw = hex.EncodeToString(st.Marshal())
 	return raw, nil
 }
+
+// AddOwnerSignature puts the signature to the slot of the owner position,
+// the signature slots grow to the owners count of the safe
+func (tx *SafeTransaction) AddOwnerSignature(owners []common.Address, owner common.Address, sig []byte) error {
+	err := tx.checkSignatureSlots(owners)
+	if err != nil {
+		return err
+	}
+	tx.Signatures = tx.Signatures[:min(len(tx.Signatures), len(owners))]
+	index := slices.Index(owners, owner)
+	if index < 0 {
+		return fmt.Errorf("invalid safe %s owner %s", tx.SafeAddress, owner.Hex())
+	}
+	for len(tx.Signatures) < len(owners) {
+		tx.Signatures = append(tx.Signatures, nil)
+	}
+	tx.Signatures[index] = sig
+	return nil
+}
+
+// ValidateSignatures ensures all signatures are made by the owners at the same
+// position, and there are enough signatures to satisfy the threshold
+func (tx *SafeTransaction) ValidateSignatures(owners []common.Address, threshold int64) error {
+	if threshold < 1 || threshold > int64(len(owners)) {
+		return fmt.Errorf("invalid threshold %d for owners %d", threshold, len(owners))
+	}
+	err := tx.checkSignatureSlots(owners)
+	if err != nil {
+		return err
+	}
+	var signed int64
+	for i, sig := range tx.Signatures {
+		if len(sig) == 0 {
+			continue
+		}
+		signer, err := tx.RecoverSigner(sig)
+		if err != nil {
+			return err
+		}
+		if signer != owners[i] {
+			return fmt.Errorf("invalid signature %d signer %s owner %s", i, signer.Hex(), owners[i].Hex())
+		}
+		signed += 1
+	}
+	if signed < threshold {
+		return fmt.Errorf("insufficient signatures %d for threshold %d", signed, threshold)
+	}
+	return nil
+}
+
+// checkSignatureSlots allows the empty slots past the owners, e.g. the
+// legacy transactions always have at least three slots
+func (tx *SafeTransaction) checkSignatureSlots(owners []common.Address) error {
+	for i := len(owners); i < len(tx.Signatures); i++ {
+		if len(tx.Signatures[i]) > 0 {
+			return fmt.Errorf("invalid signatures count %d for owners %d", len(tx.Signatures), len(owners))
+		}
+	}
+	return nil
+}
+
+// RecoverSigner returns the owner address of the signature to the message
+func (tx *SafeTransaction) RecoverSigner(sig []byte) (common.Address, error) {
+	return recoverHashSigner(tx.Message, sig)
+}
+
+// Deprecated: use RPCClient.CheckTransactionThreshold
+func CheckTransactionThreshold(rpc, raw string) error {
+	c, err := defaultRPCClient(rpc)
+	if err != nil {
+		return err
+	}
+	return c.CheckTransactionThreshold(context.Background(), raw)
+}
+
+func (c *RPCClient) CheckTransactionThreshold(ctx context.Context, raw string) error {
+	b, err := hex.DecodeString(raw)
+	if err != nil {
+		return err
+	}
+	st, err := UnmarshalSafeTransaction(b)
+	if err != nil {
+		return err
+	}
+	os, err := c.GetOwners(ctx, st.SafeAddress)
+	if err != nil {
+		return err
+	}
+	threshold, err := c.GetThreshold(ctx, st.SafeAddress)
+	if err != nil {
+		return err
+	}
+	return st.ValidateSignatures(os, threshold)
+}
+
+func bigBytes(b *big.Int) []byte {
+	if b == nil {
+		return nil
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,113 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalEthTx(t *testing.T) {
+	assert := assert.New(t)
+
+	legacy, err := UnmarshalSafeTransaction(common.FromHex(testLegacyTransaction))
+	assert.Nil(err)
+	assert.Len(legacy.Signatures, 3)
+
+	st, err := UnmarshalSafeTransaction(legacy.Marshal())
+	assert.Nil(err)
+	assert.Equal(legacy, st)
+
+	st.SafeTxGas = big.NewInt(50000)
+	st.BaseGas = big.NewInt(21000)
+	st.GasPrice = big.NewInt(1000000000)
+	st.GasToken = legacy.Destination
+	st.RefundReceiver = legacy.Destination
+	st.Signatures = [][]byte{nil, {1, 2, 3}, nil, {4, 5, 6}, nil}
+	b := st.Marshal()
+	dt, err := UnmarshalSafeTransaction(b)
+	assert.Nil(err)
+	assert.Equal(st, dt)
+	assert.Equal(int64(50000), dt.SafeTxGas.Int64())
+	assert.Len(dt.Signatures, 5)
+
+	_, err = UnmarshalSafeTransaction(b[:len(b)-2])
+	assert.NotNil(err)
+
+	keys, owners := testOwners(2)
+	assert.NotNil(legacy.ValidateSignatures(owners, 1))
+	sig, err := SignTx(testLegacyTransaction, hex.EncodeToString(crypto.FromECDSA(keys[1])))
+	assert.Nil(err)
+	err = legacy.AddOwnerSignature(owners, owners[1], common.FromHex(sig))
+	assert.Nil(err)
+	assert.Len(legacy.Signatures, 2)
+	assert.Nil(legacy.ValidateSignatures(owners, 1))
+	legacy.Signatures = append(legacy.Signatures, common.FromHex(sig))
+	assert.NotNil(legacy.ValidateSignatures(owners, 1))
+}
+
+func TestEthTxThreshold(t *testing.T) {
+	assert := assert.New(t)
+
+	keys, owners := testOwners(5)
+	tx := testTransfer(t, 137)
+	assert.Len(tx.Signatures, 0)
+
+	for _, i := range []int{4, 1} {
+		sig, err := SignTx(hex.EncodeToString(tx.Marshal()), hex.EncodeToString(crypto.FromECDSA(keys[i])))
+		assert.Nil(err)
+		err = tx.AddOwnerSignature(owners, owners[i], common.FromHex(sig))
+		assert.Nil(err)
+	}
+	assert.Len(tx.Signatures, 5)
+	assert.Nil(tx.ValidateSignatures(owners, 2))
+	assert.NotNil(tx.ValidateSignatures(owners, 3))
+	assert.NotNil(tx.ValidateSignatures(owners, 6))
+	assert.NotNil(tx.ValidateSignatures(owners[:4], 2))
+
+	tx.Signatures[0], tx.Signatures[1] = tx.Signatures[1], nil
+	assert.NotNil(tx.ValidateSignatures(owners, 1))
+	err := tx.AddOwnerSignature(owners, common.HexToAddress(testReceiverAddress), tx.Signatures[0])
+	assert.NotNil(err)
+}
+
+func TestEthApproveTx(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	token := "0xc2132D05D31c914a87C6611C10748AEb04B58e8F"
+	tx, err := CreateTransaction(ctx, TypeERC20ApproveTx, 137, testTransactionId, testSafeAddress, testReceiverAddress, token, "500", big.NewInt(1))
+	assert.Nil(err)
+	assert.Equal(token, tx.Destination.Hex())
+	_, err = tx.ExtractOutputs()
+	assert.NotNil(err)
+	actions, err := tx.DecodeActions()
+	assert.Nil(err)
+	assert.Len(actions, 1)
+	assert.Equal("approve", actions[0].Method)
+	assert.Equal(big.NewInt(500), actions[0].Args["_value"])
+
+	tx, err = CreateERC20ApproveTransaction(ctx, 137, testTransactionId, testSafeAddress, token, testReceiverAddress, big.NewInt(500), big.NewInt(0), big.NewInt(1))
+	assert.Nil(err)
+	assert.Equal(uint8(0), tx.Operation)
+
+	tx, err = CreateERC20ApproveTransaction(ctx, 137, testTransactionId, testSafeAddress, token, testReceiverAddress, big.NewInt(500), big.NewInt(100), big.NewInt(1))
+	assert.Nil(err)
+	assert.Equal(uint8(1), tx.Operation)
+	assert.Equal(EthereumMultiSendAddress, tx.Destination.Hex())
+	_, err = tx.ExtractOutputs()
+	assert.NotNil(err)
+	actions, err = tx.DecodeActions()
+	assert.Nil(err)
+	assert.Len(actions, 2)
+	assert.Equal(int64(0), actions[0].Args["_value"].(*big.Int).Int64())
+	assert.Equal(big.NewInt(500), actions[1].Args["_value"])
+	assert.Equal(common.HexToAddress(testReceiverAddress), actions[1].Args["_spender"])
+
+	_, err = CreateERC20ApproveTransaction(ctx, 137, testTransactionId, testSafeAddress, "0x1234", testReceiverAddress, big.NewInt(500), big.NewInt(100), big.NewInt(1))
+	assert.NotNil(err)
+}
@@ -0,0 +1,113 @@
+package operation
+
+import (
+	"fmt"
+
+	"github.com/MixinNetwork/go-safe-sdk/common"
+	"github.com/MixinNetwork/go-safe-sdk/types"
+	"github.com/gofrs/uuid/v5"
+)
+
+// ApproveAccount approves the safe account proposal by proposalId, the signature
+// should be signed by holder with the message APPROVE:PROPOSAL-ID:ADDRESS
+func ApproveAccount(operationId, publicKey, proposalId string, signature []byte, chain byte) (*types.Operation, error) {
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeApproveAccount, ActionEthereumSafeApproveAccount)
+	if err != nil {
+		return nil, err
+	}
+	return buildReferenceOperation(operationId, publicKey, action, curve, proposalId, signature)
+}
+
+// ApproveTransaction approves the transaction by transactionId, the hash
+// is the reference to the signed raw transaction stored in Mixin Kernel
+func ApproveTransaction(operationId, publicKey, transactionId string, hash []byte, chain byte) (*types.Operation, error) {
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeApproveTransaction, ActionEthereumSafeApproveTransaction)
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("invalid reference hash %x", hash)
+	}
+	return buildReferenceOperation(operationId, publicKey, action, curve, transactionId, hash)
+}
+
+// RevokeTransaction revokes the transaction by transactionId, the signature
+// should be signed by holder or observer with the message REVOKE:TX-ID:TX-HASH
+func RevokeTransaction(operationId, publicKey, transactionId string, signature []byte, chain byte) (*types.Operation, error) {
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeRevokeTransaction, ActionEthereumSafeRevokeTransaction)
+	if err != nil {
+		return nil, err
+	}
+	return buildReferenceOperation(operationId, publicKey, action, curve, transactionId, signature)
+}
+
+// CloseAccount closes the safe account with the transaction by transactionId,
+// the hash is the reference to the signed raw transaction stored in Mixin Kernel
+func CloseAccount(operationId, publicKey, transactionId string, hash []byte, chain byte) (*types.Operation, error) {
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeCloseAccount, ActionEthereumSafeCloseAccount)
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("invalid reference hash %x", hash)
+	}
+	return buildReferenceOperation(operationId, publicKey, action, curve, transactionId, hash)
+}
+
+// CloseAccountByInheritance closes the safe account after the inheritance lock
+// by lockId expired, the hash is the reference to the signed raw transaction
+func CloseAccountByInheritance(operationId, publicKey, lockId string, hash []byte, chain byte) (*types.Operation, error) {
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeCloseAccountByInheritance, ActionEthereumSafeCloseAccountByInheritance)
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("invalid reference hash %x", hash)
+	}
+	return buildReferenceOperation(operationId, publicKey, action, curve, lockId, hash)
+}
+
+// RefundTransaction refunds the assets of the pending transaction by
+// transactionId, only available for Ethereum like chains
+func RefundTransaction(operationId, publicKey, transactionId string, chain byte) (*types.Operation, error) {
+	action, curve, err := chainActionCurve(chain, 0, ActionEthereumSafeRefundTransaction)
+	if err != nil {
+		return nil, err
+	}
+	return buildReferenceOperation(operationId, publicKey, action, curve, transactionId, nil)
+}
+
+func buildReferenceOperation(operationId, publicKey string, action, curve uint8, reference string, data []byte) (*types.Operation, error) {
+	rid, err := uuid.FromString(reference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid %s", reference)
+	}
+	extra := rid.Bytes()
+	extra = append(extra, data...)
+	op := &types.Operation{
+		Id:     operationId,
+		Type:   action,
+		Curve:  curve,
+		Public: publicKey,
+		Extra:  extra,
+	}
+	return op, nil
+}
+
+func chainActionCurve(chain byte, bitcoinAction, ethereumAction uint8) (uint8, uint8, error) {
+	c, err := common.ReadChain(chain)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid chain: %d", chain)
+	}
+	var action uint8
+	switch c.Family {
+	case common.FamilyBitcoin:
+		action = bitcoinAction
+	case common.FamilyEthereum:
+		action = ethereumAction
+	}
+	if action == 0 {
+		return 0, 0, fmt.Errorf("invalid action for chain: %d", chain)
+	}
+	return action, c.Curve, nil
+}
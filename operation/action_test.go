@@ -0,0 +1,138 @@
+package operation
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/MixinNetwork/go-safe-sdk/common"
+	"github.com/MixinNetwork/go-safe-sdk/ethereum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	holder := "03911c1ef3960be7304596cfa6073b1d65ad43b421a4c272142cc7a8369b510c56"
+	hash, _ := hex.DecodeString("6e85e33c27105143808a5fdcdea96f3ef7cb2d8553fcb7680b10c3778c55059a")
+	sig, _ := hex.DecodeString("3044022034b2fe1cd0f0c36a9ca6e5ed1a3d7a56e5f0a2b3df0e0d2d8bd2cdbfc1c9c65a02203d0b49ee66b0fe8a9ed1fb2f1d44d58b0f6e1d1e6f3d2e54d4c7b1b4e43e6b1a")
+
+	op, err := ApproveAccount("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", sig, SafeChainBitcoin)
+	assert.Nil(err)
+	assert.Equal(uint8(ActionBitcoinSafeApproveAccount), op.Type)
+	assert.Equal(uint8(CurveSecp256k1ECDSABitcoin), op.Curve)
+	assert.Equal("ce8491f23fde4d2ea4cc4fcf707889c3"+hex.EncodeToString(sig), hex.EncodeToString(op.Extra))
+
+	op, err = ApproveTransaction("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", hash, SafeChainPolygon)
+	assert.Nil(err)
+	assert.Equal(uint8(ActionEthereumSafeApproveTransaction), op.Type)
+	assert.Equal(uint8(CurveSecp256k1ECDSAPolygon), op.Curve)
+	assert.Len(op.Extra, 48)
+
+	op, err = RevokeTransaction("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", sig, SafeChainLitecoin)
+	assert.Nil(err)
+	assert.Equal(uint8(ActionBitcoinSafeRevokeTransaction), op.Type)
+	assert.Equal(uint8(CurveSecp256k1ECDSALitecoin), op.Curve)
+
+	op, err = CloseAccount("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", hash[:16], SafeChainBitcoin)
+	assert.NotNil(err)
+	assert.Nil(op)
+
+	op, err = CloseAccountByInheritance("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, "af36f755-a48a-3408-8a97-092007f9e2d2", hash, SafeChainEthereum)
+	assert.Nil(err)
+	assert.Equal(uint8(ActionEthereumSafeCloseAccountByInheritance), op.Type)
+	assert.Equal("af36f755a48a34088a97092007f9e2d2"+hex.EncodeToString(hash), hex.EncodeToString(op.Extra))
+
+	op, err = RefundTransaction("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", SafeChainBitcoin)
+	assert.NotNil(err)
+	assert.Nil(op)
+	op, err = RefundTransaction("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", SafeChainMVM)
+	assert.Nil(err)
+	assert.Equal(uint8(ActionEthereumSafeRefundTransaction), op.Type)
+	assert.Len(op.Extra, 16)
+}
+
+func TestRegisteredChainOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	err := common.RegisterChain(&common.ChainInfo{
+		Chain:      200,
+		AssetId:    "9a2e5a8f-4a5d-4b3e-9a1f-2f3f0e7c1b20",
+		Name:       "Ether",
+		Symbol:     "ETH",
+		Curve:      200 + CurveSecp256k1ECDSAEthereum,
+		Family:     common.FamilyEthereum,
+		EvmChainId: 31337,
+		Precision:  18,
+	})
+	assert.Nil(err)
+	err = common.RegisterChain(&common.ChainInfo{
+		Chain:      201,
+		AssetId:    "9a2e5a8f-4a5d-4b3e-9a1f-2f3f0e7c1b21",
+		Curve:      200 + CurveSecp256k1ECDSAEthereum,
+		Family:     common.FamilyEthereum,
+		EvmChainId: 31338,
+		Precision:  18,
+	})
+	assert.NotNil(err)
+
+	holder := "03911c1ef3960be7304596cfa6073b1d65ad43b421a4c272142cc7a8369b510c56"
+	op, err := ProposeTransaction("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, TransactionTypeNormal, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", "0xA03A8590BB3A2cA5c747c8b99C63DA399424a055", 200)
+	assert.Nil(err)
+	assert.Equal(uint8(ActionEthereumSafeProposeTransaction), op.Type)
+	assert.Equal(uint8(200+CurveSecp256k1ECDSAEthereum), op.Curve)
+
+	_, err = ProposeTransaction("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, TransactionTypeNormal, "ce8491f2-3fde-4d2e-a4cc-4fcf707889c3", "0xA03A8590BB3A2cA5c747c8b99C63DA399424a055", 202)
+	assert.NotNil(err)
+}
+
+func TestEvmChainOperation(t *testing.T) {
+	assert := assert.New(t)
+
+	holder := "03911c1ef3960be7304596cfa6073b1d65ad43b421a4c272142cc7a8369b510c56"
+	owners := []string{"fcb87491-4fa0-4c2f-b387-262b63cbc112"}
+	for chain, curve := range map[byte]int{
+		SafeChainArbitrum:  CurveSecp256k1ECDSAArbitrum,
+		SafeChainOptimism:  CurveSecp256k1ECDSAOptimism,
+		SafeChainBase:      CurveSecp256k1ECDSABase,
+		SafeChainBNB:       CurveSecp256k1ECDSABNB,
+		SafeChainAvalanche: CurveSecp256k1ECDSAAvalanche,
+	} {
+		op, err := ProposeAccount("358c0e9e-8d9c-4e0f-acde-8945a859763a", holder, owners, 1, chain, 24)
+		assert.Nil(err)
+		assert.Equal(uint8(ActionEthereumSafeProposeAccount), op.Type)
+		assert.Equal(uint8(curve), op.Curve)
+	}
+	assert.Equal(int64(8453), ethereum.GetEvmChainID(SafeChainBase))
+	assert.Equal(SafeBNBChainId, ethereum.GetMixinChainID(SafeChainBNB))
+	assert.Equal("AVAX", ethereum.GetGasSymbol(SafeChainAvalanche))
+
+	multiSend := "0x40A2aCCbd92BCA938b02010E17A5b8929b49130D"
+	err := ethereum.RegisterSafeDeployment(10, &ethereum.SafeDeployment{MultiSend: "0x1234"})
+	assert.NotNil(err)
+	err = ethereum.RegisterSafeDeployment(10, &ethereum.SafeDeployment{MultiSend: multiSend})
+	assert.Nil(err)
+	d := ethereum.GetSafeDeployment(10)
+	assert.Equal(multiSend, d.MultiSend)
+	assert.Equal(ethereum.EthereumSafeL2Address, d.SafeL2)
+	assert.Equal(ethereum.EthereumSafeGuardAddress, d.SafeGuard)
+	assert.Equal(ethereum.EthereumMultiSendAddress, ethereum.GetSafeDeployment(42161).MultiSend)
+	assert.Equal(ethereum.EthereumSafeGuardAddress, ethereum.GetSafeDeployment(43114).SafeGuard)
+	assert.Equal("", ethereum.GetSafeDeployment(11155111).SafeGuard)
+
+	safe, observer := "0x3e7a4c7e2fA3dD1c3D8d4E3b8b4C8c0E5B7a3a11", "0xA03A8590BB3A2cA5c747c8b99C63DA399424a055"
+	_, err = ethereum.CreateEnableGuardTransaction(context.Background(), 11155111, "b0d6e8a1-0ba4-4e1a-a90c-3e1c3c4f5c2d", safe, observer, big.NewInt(3600))
+	assert.NotNil(err)
+	_, err = ethereum.CreateEnableGuardTransaction(context.Background(), 8453, "b0d6e8a1-0ba4-4e1a-a90c-3e1c3c4f5c2d", safe, observer, big.NewInt(3600))
+	assert.Nil(err)
+
+	outputs := []*ethereum.Output{{
+		Destination:  "0xA03A8590BB3A2cA5c747c8b99C63DA399424a055",
+		Amount:       big.NewInt(1000),
+		TokenAddress: ethereum.EthereumEmptyAddress,
+	}}
+	tx, err := ethereum.CreateMultiSendTransaction(context.Background(), 10, "b0d6e8a1-0ba4-4e1a-a90c-3e1c3c4f5c2d", "0x3e7a4c7e2fA3dD1c3D8d4E3b8b4C8c0E5B7a3a11", outputs, big.NewInt(0))
+	assert.Nil(err)
+	assert.Equal(multiSend, tx.Destination.Hex())
+}
@@ -3,18 +3,21 @@ package operation
 import (
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 
 	"github.com/MixinNetwork/go-safe-sdk/bitcoin"
+	"github.com/MixinNetwork/go-safe-sdk/common"
 	"github.com/MixinNetwork/go-safe-sdk/ethereum"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 func SignSafeMessage(msg, priv string, chain byte) (string, error) {
-	switch chain {
-	case SafeChainBitcoin, SafeChainLitecoin:
+	switch common.ChainFamily(chain) {
+	case common.FamilyBitcoin:
 		hash, err := HashMessageForSignature(msg, chain)
 		if err != nil {
 			return "", err
@@ -26,7 +29,7 @@ func SignSafeMessage(msg, priv string, chain byte) (string, error) {
 		private, _ := btcec.PrivKeyFromBytes(b)
 		sig := ecdsa.Sign(private, hash)
 		return base64.RawURLEncoding.EncodeToString(sig.Serialize()), nil
-	case SafeChainEthereum, SafeChainMVM, SafeChainPolygon:
+	case common.FamilyEthereum:
 		hash, err := HashMessageForSignature(msg, chain)
 		if err != nil {
 			return "", err
@@ -47,12 +50,34 @@ func SignSafeMessage(msg, priv string, chain byte) (string, error) {
 }
 
 func VerifySafeMessage(public string, msg, sig []byte, chain byte) error {
-	switch chain {
-	case SafeChainBitcoin, SafeChainLitecoin:
+	switch common.ChainFamily(chain) {
+	case common.FamilyBitcoin:
 		return bitcoin.VerifySignatureDER(public, msg, sig)
-	case SafeChainEthereum, SafeChainMVM, SafeChainPolygon:
+	case common.FamilyEthereum:
 		return ethereum.VerifyMessageSignature(public, msg, sig)
 	default:
 		return fmt.Errorf("invalid chain: %d", chain)
 	}
 }
+
+// SignSafeTypedData signs the EIP-712 typed data in JSON as the safe owner,
+// and the signature is verified by the EIP-1271 isValidSignature of the safe
+func SignSafeTypedData(typedData, safeAddress, priv string, chain byte) (string, error) {
+	if common.ChainFamily(chain) != common.FamilyEthereum {
+		return "", fmt.Errorf("invalid chain: %d", chain)
+	}
+	var typed apitypes.TypedData
+	err := json.Unmarshal([]byte(typedData), &typed)
+	if err != nil {
+		return "", err
+	}
+	hash, err := ethereum.GetSafeTypedDataHash(ethereum.GetEvmChainID(int64(chain)), safeAddress, typed)
+	if err != nil {
+		return "", err
+	}
+	sig, err := ethereum.SignSafeMessageHash(hash, priv)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sig), nil
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"strings"
 
+	sc "github.com/MixinNetwork/go-safe-sdk/common"
 	"github.com/MixinNetwork/mixin/crypto"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -24,18 +25,34 @@ const (
 	TestFactoryContractAddress = "0x4D17777E0AC12C6a0d4DEF1204278cFEAe142a1E"
 	ProdFactoryContractAddress = "0x3c763e46456915922a4647a1E3f3B8916c93171e"
 
-	SafeChainBitcoin  = 1
-	SafeChainEthereum = 2
-	SafeChainMixin    = 3
-	SafeChainMVM      = 4
-	SafeChainLitecoin = 5
-	SafeChainPolygon  = 6
+	SafeChainBitcoin  = sc.ChainBitcoin
+	SafeChainEthereum = sc.ChainEthereum
+	SafeChainMixin    = sc.ChainMixinKernel
+	SafeChainMVM      = sc.ChainMVM
+	SafeChainLitecoin = sc.ChainLitecoin
+	SafeChainPolygon  = sc.ChainPolygon
+
+	SafeChainDogecoin    = sc.ChainDogecoin
+	SafeChainBitcoinCash = sc.ChainBitcoinCash
+	SafeChainArbitrum    = sc.ChainArbitrum
+	SafeChainOptimism    = sc.ChainOptimism
+	SafeChainBase        = sc.ChainBase
+	SafeChainBNB         = sc.ChainBNB
+	SafeChainAvalanche   = sc.ChainAvalanche
 
 	SafeBitcoinChainId  = "c6d0c728-2624-429b-8e0d-d9d19b6592fa"
 	SafeLitecoinChainId = "76c802a2-7c88-447f-a93e-c29c9e5dd9c8"
 	SafeEthereumChainId = "43d61dcd-e413-450d-80b8-101d5e903357"
 	SafeMVMChainId      = "a0ffd769-5850-4b48-9651-d2ae44a3e64d"
 	SafePolygonChainId  = "b7938396-3f94-4e0a-9179-d3440718156f"
+
+	SafeDogecoinChainId    = "6770a1e5-6086-44d5-b60f-545f9d9e8ffd"
+	SafeBitcoinCashChainId = "fd11b6e3-0b87-41f1-a41f-f0e9b49e5bf0"
+	SafeArbitrumChainId    = "8c590110-1abc-3697-84f2-05214e6516aa"
+	SafeOptimismChainId    = "62d5b01f-24ee-4c96-8214-8e04981d05f2"
+	SafeBaseChainId        = "3fb612c5-6844-3979-ae4a-5a84e79da870"
+	SafeBNBChainId         = "1949e683-6a08-49e2-b087-d6b72398588f"
+	SafeAvalancheChainId   = "cbc77539-0a20-4666-8c8a-4ded62b36f0a"
 )
 
 var (
@@ -99,19 +116,11 @@ func GetFactoryAssetAddress(receiver, assetId, symbol, name, holder string) comm
 }
 
 func GetSafeBTCAssetId(reciever, assetId, holder, symbol, name string) (string, error) {
-	switch assetId {
-	case SafeBitcoinChainId:
-		symbol, name = "BTC", "Bitcoin"
-	case SafeLitecoinChainId:
-		symbol, name = "LTC", "Litecoin"
-	case SafeEthereumChainId, SafeMVMChainId:
-		symbol, name = "ETH", "Ether"
-	case SafePolygonChainId:
-		symbol, name = "MATIC", "Polygon"
-	default:
-		if name == "" || symbol == "" {
-			return "", fmt.Errorf("invalid asset symbol %s or name %s", symbol, name)
-		}
+	c, err := sc.ReadChainByAssetId(assetId)
+	if err == nil {
+		symbol, name = c.Symbol, c.Name
+	} else if name == "" || symbol == "" {
+		return "", fmt.Errorf("invalid asset symbol %s or name %s", symbol, name)
 	}
 	addr := GetFactoryAssetAddress(reciever, assetId, symbol, name, holder)
 	assetKey := strings.ToLower(addr.String())
@@ -13,11 +13,19 @@ const (
 	BitcoinAssetId = "c6d0c728-2624-429b-8e0d-d9d19b6592fa"
 	PusdAssetId    = "31d2ea9c-95eb-3355-b65b-ba096853bc18"
 
-	CurveSecp256k1ECDSABitcoin  = 1
-	CurveSecp256k1ECDSAEthereum = 2
-	CurveSecp256k1ECDSALitecoin = 100 + CurveSecp256k1ECDSABitcoin
-	CurveSecp256k1ECDSAMVM      = 100 + CurveSecp256k1ECDSAEthereum
-	CurveSecp256k1ECDSAPolygon  = 110 + CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSABitcoin  = common.CurveSecp256k1ECDSABitcoin
+	CurveSecp256k1ECDSAEthereum = common.CurveSecp256k1ECDSAEthereum
+	CurveSecp256k1ECDSALitecoin = common.CurveSecp256k1ECDSALitecoin
+	CurveSecp256k1ECDSAMVM      = common.CurveSecp256k1ECDSAMVM
+	CurveSecp256k1ECDSAPolygon  = common.CurveSecp256k1ECDSAPolygon
+
+	CurveSecp256k1ECDSADogecoin    = common.CurveSecp256k1ECDSADogecoin
+	CurveSecp256k1ECDSABitcoinCash = common.CurveSecp256k1ECDSABitcoinCash
+	CurveSecp256k1ECDSAArbitrum    = common.CurveSecp256k1ECDSAArbitrum
+	CurveSecp256k1ECDSAOptimism    = common.CurveSecp256k1ECDSAOptimism
+	CurveSecp256k1ECDSABase        = common.CurveSecp256k1ECDSABase
+	CurveSecp256k1ECDSABNB         = common.CurveSecp256k1ECDSABNB
+	CurveSecp256k1ECDSAAvalanche   = common.CurveSecp256k1ECDSAAvalanche
 
 	// For all Bitcoin like chains
 	ActionBitcoinSafeProposeAccount            = 110
@@ -46,25 +54,9 @@ const (
 )
 
 func ProposeAccount(operationId, publicKey string, owners []string, threshold, chain byte, timeLock uint16) (*types.Operation, error) {
-	var action, curve uint8
-	switch chain {
-	case SafeChainBitcoin:
-		action = ActionBitcoinSafeProposeAccount
-		curve = CurveSecp256k1ECDSABitcoin
-	case SafeChainLitecoin:
-		action = ActionBitcoinSafeProposeAccount
-		curve = CurveSecp256k1ECDSALitecoin
-	case SafeChainEthereum:
-		action = ActionEthereumSafeProposeAccount
-		curve = CurveSecp256k1ECDSAEthereum
-	case SafeChainMVM:
-		action = ActionEthereumSafeProposeAccount
-		curve = CurveSecp256k1ECDSAMVM
-	case SafeChainPolygon:
-		action = ActionEthereumSafeProposeAccount
-		curve = CurveSecp256k1ECDSAPolygon
-	default:
-		return nil, fmt.Errorf("invalid chain: %d", chain)
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeProposeAccount, ActionEthereumSafeProposeAccount)
+	if err != nil {
+		return nil, err
 	}
 
 	op := types.Operation{
@@ -89,25 +81,9 @@ func ProposeAccount(operationId, publicKey string, owners []string, threshold, c
 }
 
 func ProposeTransaction(operationId, publicKey string, typ byte, head, destination string, chain byte) (*types.Operation, error) {
-	var action, curve uint8
-	switch chain {
-	case SafeChainBitcoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSABitcoin
-	case SafeChainLitecoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSALitecoin
-	case SafeChainEthereum:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAEthereum
-	case SafeChainMVM:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAMVM
-	case SafeChainPolygon:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAPolygon
-	default:
-		return nil, fmt.Errorf("invalid chain: %d", chain)
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeProposeTransaction, ActionEthereumSafeProposeTransaction)
+	if err != nil {
+		return nil, err
 	}
 
 	extra := []byte{typ}
@@ -124,25 +100,9 @@ func ProposeTransaction(operationId, publicKey string, typ byte, head, destinati
 }
 
 func ProposeBatchTransaction(operationId, publicKey string, typ byte, head string, hash []byte, chain byte) (*types.Operation, error) {
-	var action, curve uint8
-	switch chain {
-	case SafeChainBitcoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSABitcoin
-	case SafeChainLitecoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSALitecoin
-	case SafeChainEthereum:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAEthereum
-	case SafeChainMVM:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAMVM
-	case SafeChainPolygon:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAPolygon
-	default:
-		return nil, fmt.Errorf("invalid chain: %d", chain)
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeProposeTransaction, ActionEthereumSafeProposeTransaction)
+	if err != nil {
+		return nil, err
 	}
 
 	extra := []byte{typ}
@@ -159,25 +119,9 @@ func ProposeBatchTransaction(operationId, publicKey string, typ byte, head strin
 }
 
 func ProposeCancelTransaction(operationId, publicKey string, head, destination string, chain byte, cancelId string) (*types.Operation, error) {
-	var action, curve uint8
-	switch chain {
-	case SafeChainBitcoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSABitcoin
-	case SafeChainLitecoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSALitecoin
-	case SafeChainEthereum:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAEthereum
-	case SafeChainMVM:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAMVM
-	case SafeChainPolygon:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAPolygon
-	default:
-		return nil, fmt.Errorf("invalid chain: %d", chain)
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeProposeTransaction, ActionEthereumSafeProposeTransaction)
+	if err != nil {
+		return nil, err
 	}
 
 	extra := []byte{TransactionTypeCancel}
@@ -195,25 +139,9 @@ func ProposeCancelTransaction(operationId, publicKey string, head, destination s
 }
 
 func ProposeInheritanceTransaction(operationId, publicKey string, typ byte, head, destination string, chain byte, lockID, hash string, duration uint16) (*types.Operation, error) {
-	var action, curve uint8
-	switch chain {
-	case SafeChainBitcoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSABitcoin
-	case SafeChainLitecoin:
-		action = ActionBitcoinSafeProposeTransaction
-		curve = CurveSecp256k1ECDSALitecoin
-	case SafeChainEthereum:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAEthereum
-	case SafeChainMVM:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAMVM
-	case SafeChainPolygon:
-		action = ActionEthereumSafeProposeTransaction
-		curve = CurveSecp256k1ECDSAPolygon
-	default:
-		return nil, fmt.Errorf("invalid chain: %d", chain)
+	action, curve, err := chainActionCurve(chain, ActionBitcoinSafeProposeTransaction, ActionEthereumSafeProposeTransaction)
+	if err != nil {
+		return nil, err
 	}
 
 	extra := []byte{typ}
@@ -4,14 +4,15 @@ import (
 	"fmt"
 
 	"github.com/MixinNetwork/go-safe-sdk/bitcoin"
+	"github.com/MixinNetwork/go-safe-sdk/common"
 	"github.com/MixinNetwork/go-safe-sdk/ethereum"
 )
 
 func SignSafeTx(rawStr, privateStr string, chain byte) (string, error) {
-	switch chain {
-	case SafeChainBitcoin, SafeChainLitecoin:
+	switch common.ChainFamily(chain) {
+	case common.FamilyBitcoin:
 		return bitcoin.SignTx(rawStr, privateStr, chain)
-	case SafeChainEthereum, SafeChainMVM, SafeChainPolygon:
+	case common.FamilyEthereum:
 		return ethereum.SignTx(rawStr, privateStr)
 	default:
 		return "", fmt.Errorf("invalid chain: %d", chain)
@@ -19,10 +20,10 @@ func SignSafeTx(rawStr, privateStr string, chain byte) (string, error) {
 }
 
 func HashMessageForSignature(msg string, chain byte) ([]byte, error) {
-	switch chain {
-	case SafeChainBitcoin, SafeChainLitecoin:
+	switch common.ChainFamily(chain) {
+	case common.FamilyBitcoin:
 		return bitcoin.HashMessageForSignature(msg, chain)
-	case SafeChainEthereum, SafeChainMVM, SafeChainPolygon:
+	case common.FamilyEthereum:
 		return ethereum.HashMessageForSignature(msg)
 	default:
 		return nil, fmt.Errorf("invalid chain: %d", chain)
@@ -30,10 +31,10 @@ func HashMessageForSignature(msg string, chain byte) ([]byte, error) {
 }
 
 func CheckTransactionPartiallySignedBy(raw, public string, chain byte) bool {
-	switch chain {
-	case SafeChainBitcoin, SafeChainLitecoin:
+	switch common.ChainFamily(chain) {
+	case common.FamilyBitcoin:
 		return bitcoin.CheckTransactionPartiallySignedBy(raw, public)
-	case SafeChainEthereum, SafeChainMVM, SafeChainPolygon:
+	case common.FamilyEthereum:
 		return ethereum.CheckTransactionPartiallySignedBy(raw, public)
 	default:
 		return false
@@ -2,13 +2,19 @@ package operation
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"log"
 	"math/big"
+	"slices"
 	"testing"
 
 	"github.com/MixinNetwork/go-safe-sdk/bitcoin"
 	"github.com/MixinNetwork/go-safe-sdk/common"
 	"github.com/MixinNetwork/go-safe-sdk/ethereum"
+	"github.com/MixinNetwork/go-safe-sdk/ethereum/abi"
+	gc "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -56,7 +62,8 @@ func TestParseEthTx(t *testing.T) {
 	raw := "00000000000000890000000000000001004066336238653462336561303462303137636630383961323039363962323661333264353232333836636331343064663734343435383535376133373065636431002a307834663539373461303536303239454641376534423762353161374262636238464563364538393730001438869bf66a61cf6bdb996a6ae40d5853fd43b526000001448d80ff0a000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000ee00a03a8590bb3a2ca5c747c8b99c63da399424a05500000000000000000000000000000000000000000000000000005af3107a4000000000000000000000000000000000000000000000000000000000000000000000c2132d05d31c914a87c6611c10748aeb04b58e8f00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000044a9059cbb000000000000000000000000a03a8590bb3a2ca5c747c8b99c63da399424a05500000000000000000000000000000000000000000000000000000000000000c80000000000000000000000000000000000000001010020288302032801fdd390a9714e4c2b8421658c9d4723bfcc8b4431b0aae098452100022c2c"
 	st, err := ethereum.UnmarshalSafeTransaction(common.DecodeHexOrPanic(raw))
 	assert.Nil(err)
-	os := st.ExtractOutputs()
+	os, err := st.ExtractOutputs()
+	assert.Nil(err)
 	assert.Len(os, 2)
 	assert.Equal(os[0].TokenAddress, ethereum.EthereumEmptyAddress)
 	assert.Equal(os[0].Destination, "0xA03A8590BB3A2cA5c747c8b99C63DA399424a055")
@@ -64,4 +71,62 @@ func TestParseEthTx(t *testing.T) {
 	assert.Equal(os[1].TokenAddress, "0xc2132D05D31c914a87C6611C10748AEb04B58e8F")
 	assert.Equal(os[1].Destination, "0xA03A8590BB3A2cA5c747c8b99C63DA399424a055")
 	assert.Equal(os[1].Amount, big.NewInt(200))
+
+	multiSend, err := abi.MultiSendMetaData.GetAbi()
+	assert.Nil(err)
+	args, err := multiSend.Methods["multiSend"].Inputs.Unpack(st.Data[4:])
+	assert.Nil(err)
+	transactions := args[0].([]byte)
+	for _, data := range [][]byte{
+		st.Data[:2],
+		st.Data[:len(st.Data)-40],
+		append(gc.FromHex("0xa9059cbb"), st.Data[4:]...),
+	} {
+		tx := *st
+		tx.Data = data
+		_, err = tx.ExtractOutputs()
+		assert.NotNil(err)
+	}
+	for _, size := range []int{1, 50, 100, len(transactions) - 1} {
+		tx := *st
+		tx.Data, err = multiSend.Pack("multiSend", transactions[:size])
+		assert.Nil(err)
+		_, err = tx.ExtractOutputs()
+		assert.NotNil(err)
+	}
+	for _, index := range []int{0, 85, 170} {
+		data := slices.Clone(transactions)
+		data[index] = 1
+		tx := *st
+		tx.Data, err = multiSend.Pack("multiSend", data)
+		assert.Nil(err)
+		_, err = tx.ExtractOutputs()
+		assert.NotNil(err)
+	}
+	for _, data := range []string{"0xa9", "0xa9059cbb", "0xa9059cbb0000", "0x12345678"} {
+		tx := *st
+		tx.Operation = 0
+		tx.Data = gc.FromHex(data)
+		_, err = tx.ExtractOutputs()
+		assert.NotNil(err)
+	}
+}
+
+func TestSignSafeTypedData(t *testing.T) {
+	assert := assert.New(t)
+
+	mail := `{"types":{"EIP712Domain":[{"name":"name","type":"string"},{"name":"version","type":"string"},{"name":"chainId","type":"uint256"},{"name":"verifyingContract","type":"address"}],"Person":[{"name":"name","type":"string"},{"name":"wallet","type":"address"}],"Mail":[{"name":"from","type":"Person"},{"name":"to","type":"Person"},{"name":"contents","type":"string"}]},"primaryType":"Mail","domain":{"name":"Ether Mail","version":"1","chainId":"1","verifyingContract":"0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},"message":{"from":{"name":"Cow","wallet":"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},"to":{"name":"Bob","wallet":"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},"contents":"Hello, Bob!"}}`
+	safe := "0x3e7A4c7e2fa3dd1c3D8d4E3B8b4c8C0E5B7A3A11"
+	key, _ := crypto.GenerateKey()
+	sig, err := SignSafeTypedData(mail, safe, hex.EncodeToString(crypto.FromECDSA(key)), SafeChainPolygon)
+	assert.Nil(err)
+	var typed apitypes.TypedData
+	err = json.Unmarshal([]byte(mail), &typed)
+	assert.Nil(err)
+	hash, err := ethereum.GetSafeTypedDataHash(137, safe, typed)
+	assert.Nil(err)
+	owners := []gc.Address{crypto.PubkeyToAddress(key.PublicKey)}
+	assert.Nil(ethereum.VerifySafeMessageSignatures(hash, gc.FromHex(sig), owners, 1))
+	_, err = SignSafeTypedData(mail, safe, "", SafeChainBitcoin)
+	assert.NotNil(err)
 }